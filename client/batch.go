@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// BatchRequest is a single request of a batch sent with Client.SendBatch
+type BatchRequest struct {
+	// Method to be called
+	Method protocol.McpMethod
+	// Params of the method, nil for none
+	Params interface{}
+	// Result receives the decoded result, it must be a pointer or nil to discard the result
+	Result interface{}
+	// Err is set by SendBatch when this request failed
+	Err error
+}
+
+// SendBatch sends the requests as one JSON-RPC batch and waits for all responses
+// The returned error reports a failure to send the batch, the outcome of each request is stored in its Err field
+func (x *Client) SendBatch(ctx context.Context, batch []*BatchRequest) error {
+	if len(batch) == 0 {
+		return errors.New("batch must not be empty")
+	}
+
+	pendings := make([]*pendingRequest, 0, len(batch))
+	forgetAll := func() {
+		for _, pending := range pendings {
+			x.forgetPendingRequest(pending)
+		}
+	}
+
+	requests := make([]json.RawMessage, 0, len(batch))
+	for _, req := range batch {
+		pending, err := x.newPendingRequest(ctx, req.Method, req.Params)
+		if err != nil {
+			forgetAll()
+			return fmt.Errorf("failed to build %s request: %w", req.Method, err)
+		}
+		pendings = append(pendings, pending)
+
+		bs, err := json.Marshal(pending.request)
+		if err != nil {
+			forgetAll()
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		requests = append(requests, bs)
+	}
+
+	// Marshal and send batch
+	batchBytes, err := json.Marshal(requests)
+	if err != nil {
+		forgetAll()
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	select {
+	case x.writeChan <- batchBytes:
+	case <-ctx.Done():
+		forgetAll()
+		return ctx.Err()
+	case <-x.done:
+		forgetAll()
+		return x.Err()
+	}
+
+	// Collect the responses, the server may answer them in any order
+	for i, pending := range pendings {
+		batch[i].Err = x.awaitResponse(ctx, pending, batch[i].Result)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,6 +10,7 @@ import (
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/mcp4go/mcp4go/client/transport"
 	"github.com/mcp4go/mcp4go/pkg/logger"
@@ -17,6 +19,12 @@ import (
 	"github.com/ccheers/xpkg/sync/errgroup"
 )
 
+// ErrKeepaliveTimeout is reported when the server missed too many pings, see WithKeepalive
+var ErrKeepaliveTimeout = errors.New("keepalive timeout: server did not answer pings")
+
+// cancelNotificationTimeout bounds the time spent telling the server a request was cancelled
+const cancelNotificationTimeout = time.Second
+
 // Client is the main client implementation for the Model Context Protocol
 type Client struct {
 	options options
@@ -33,10 +41,21 @@ type Client struct {
 
 	// Handlers
 	notificationHandlers map[protocol.McpMethod]NotificationHandler
-	responseHandlers     map[int]chan *protocol.JsonrpcResponse
+	requestHandlers      map[protocol.McpMethod]RequestHandler
+	// responseHandlers are keyed by the canonical JSON of the request ID, see requestIDKey
+	responseHandlers map[string]chan *protocol.JsonrpcResponse
 
 	writeChan chan json.RawMessage
 
+	// Connection liveness, see Done and Err
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	// Roots exposed to the server, see SetRoots
+	rootsMu sync.RWMutex
+	roots   []protocol.Root
+
 	// Server capabilities
 	serverCapabilities protocol.ServerCapabilities
 	serverInfo         protocol.Implementation
@@ -46,6 +65,10 @@ type Client struct {
 // NotificationHandler is a function that processes notifications
 type NotificationHandler func(context.Context, json.RawMessage) error
 
+// RequestHandler processes a request from the server and returns the result to send back
+// An error is sent back as a JSON-RPC error, with the code of the *protocol.Error it wraps if any
+type RequestHandler func(context.Context, json.RawMessage) (interface{}, error)
+
 // ResponseHandler processes a response and returns a result or error
 type ResponseHandler func(response *protocol.JsonrpcResponse) (interface{}, error)
 
@@ -66,6 +89,21 @@ type options struct {
 	promptsListChangedHandler   func(context.Context, protocol.PromptListChangedNotification) error
 	rootsListChangedHandler     func(context.Context, protocol.RootsListChangedNotification) error
 	loggingMessageHandler       func(context.Context, protocol.LoggingMessageNotification) error
+	progressHandler             func(context.Context, protocol.ProgressNotification) error
+
+	// Request handlers
+	elicitationHandler ElicitationHandler
+	samplingHandler    SamplingHandler
+	samplingApproval   SamplingApproval
+	roots              []protocol.Root
+	rootsProvider      RootsProvider
+
+	requestIDGenerator RequestIDGenerator
+
+	// Keepalive
+	keepaliveInterval  time.Duration
+	keepaliveMaxMissed int
+	onKeepaliveTimeout func(ctx context.Context, err error)
 }
 
 // WithLogger sets the logger for the client
@@ -95,6 +133,27 @@ func WithRootsCapability(listChanged bool) Option {
 	}
 }
 
+// WithRoots enables client roots capabilities and sets the roots answered to the server, see SetRoots
+func WithRoots(roots ...protocol.Root) Option {
+	return func(o *options) {
+		if o.capabilities.Roots == nil {
+			o.capabilities.Roots = &protocol.ClientRoots{}
+		}
+		o.roots = roots
+	}
+}
+
+// WithRootsProvider enables client roots capabilities, the provider is called each time the server lists the roots
+// and takes precedence over the roots set with WithRoots or SetRoots
+func WithRootsProvider(provider RootsProvider) Option {
+	return func(o *options) {
+		if o.capabilities.Roots == nil {
+			o.capabilities.Roots = &protocol.ClientRoots{}
+		}
+		o.rootsProvider = provider
+	}
+}
+
 // WithSamplingCapability enables client sampling capabilities
 func WithSamplingCapability() Option {
 	return func(o *options) {
@@ -102,6 +161,29 @@ func WithSamplingCapability() Option {
 	}
 }
 
+// WithSamplingHandler enables client sampling capabilities, the handler answers the sampling requests of the server
+func WithSamplingHandler(handler SamplingHandler) Option {
+	return func(o *options) {
+		o.capabilities.Sampling = &protocol.ClientSampling{}
+		o.samplingHandler = handler
+	}
+}
+
+// WithSamplingApproval lets a human review the sampling requests of the server and their results, see SamplingApproval
+func WithSamplingApproval(approval SamplingApproval) Option {
+	return func(o *options) {
+		o.samplingApproval = approval
+	}
+}
+
+// WithElicitationHandler enables client elicitation capabilities, the handler answers the elicitation requests of the server
+func WithElicitationHandler(handler ElicitationHandler) Option {
+	return func(o *options) {
+		o.capabilities.Elicitation = &protocol.ClientElicitation{}
+		o.elicitationHandler = handler
+	}
+}
+
 // WithResourcesListChangedHandler sets a handler for resource list changes
 func WithResourcesListChangedHandler(handler func(context.Context, protocol.ResourceListChangedNotification) error) Option {
 	return func(o *options) {
@@ -144,6 +226,30 @@ func WithLoggingMessageHandler(handler func(context.Context, protocol.LoggingMes
 	}
 }
 
+// WithProgressHandler sets a handler for progress notifications of requests sent with a progress token
+func WithProgressHandler(handler func(context.Context, protocol.ProgressNotification) error) Option {
+	return func(o *options) {
+		o.progressHandler = handler
+	}
+}
+
+// WithRequestIDGenerator sets the generator of the request IDs, increasing integers are used by default
+func WithRequestIDGenerator(generator RequestIDGenerator) Option {
+	return func(o *options) {
+		o.requestIDGenerator = generator
+	}
+}
+
+// WithKeepalive pings the server at the given interval and closes the connection after maxMissed
+// consecutive unanswered pings, onTimeout is called with the keepalive context and ErrKeepaliveTimeout when that happens
+func WithKeepalive(interval time.Duration, maxMissed int, onTimeout func(ctx context.Context, err error)) Option {
+	return func(o *options) {
+		o.keepaliveInterval = interval
+		o.keepaliveMaxMissed = maxMissed
+		o.onKeepaliveTimeout = onTimeout
+	}
+}
+
 // defaultOptions returns the default client options
 func defaultOptions() options {
 	return options{
@@ -173,8 +279,11 @@ func NewClient(t transport.ITransport, opts ...Option) (*Client, func(), error)
 		initialized:          false,
 		mu:                   sync.Mutex{},
 		notificationHandlers: make(map[protocol.McpMethod]NotificationHandler),
-		responseHandlers:     make(map[int]chan *protocol.JsonrpcResponse),
+		requestHandlers:      make(map[protocol.McpMethod]RequestHandler),
+		responseHandlers:     make(map[string]chan *protocol.JsonrpcResponse),
 		writeChan:            make(chan json.RawMessage, 1024),
+		done:                 make(chan struct{}),
+		roots:                options.roots,
 		serverCapabilities:   protocol.ServerCapabilities{},
 		serverInfo:           protocol.Implementation{},
 		instructions:         "",
@@ -196,75 +305,109 @@ func (x *Client) Connect(ctx context.Context) error {
 	}
 	x.log.Debugf(ctx, "Connected to server")
 
-	// Register notification handlers
+	// Register notification and request handlers
 	x.registerNotificationHandlers()
+	x.registerRequestHandlers()
 
 	// Start loop
+	// The connection is closed as soon as one of the loops exits
 	x.eg.Go(func(ctx context.Context) error {
+		var cause error
 		defer func() {
 			r := recover()
 			if r != nil {
 				x.log.Errorf(ctx, "[Client][ReadLoop] panic: %v, stack:\n%s\n", r, debug.Stack())
+				cause = fmt.Errorf("read loop panic: %v", r)
 			}
+			x.closeConnection(cause)
 		}()
-		x.readLoop(ctx, reader)
+		cause = x.readLoop(ctx, reader)
 		return nil
 	})
 	x.eg.Go(func(ctx context.Context) error {
+		var cause error
 		defer func() {
 			r := recover()
 			if r != nil {
 				x.log.Errorf(ctx, "[Client][WriteLoop] panic: %v, stack:\n%s\n", r, debug.Stack())
+				cause = fmt.Errorf("write loop panic: %v", r)
 			}
+			x.closeConnection(cause)
 		}()
-		x.writeLoop(ctx, writer)
+		cause = x.writeLoop(ctx, writer)
 		return nil
 	})
 
 	defer x.log.Warnf(ctx, "Initialized server...")
 	// Initialize the server
-	return x.initialize(ctx)
+	if err := x.initialize(ctx); err != nil {
+		return err
+	}
+
+	if x.options.keepaliveInterval > 0 {
+		x.eg.Go(func(ctx context.Context) error {
+			x.keepalive(ctx)
+			return nil
+		})
+	}
+	return nil
 }
 
-func (x *Client) readLoop(ctx context.Context, reader io.Reader) {
-	decoder := json.NewDecoder(reader)
+// readLoop dispatches the messages of the server until the connection ends, it returns the cause
+func (x *Client) readLoop(ctx context.Context, reader io.Reader) error {
+	messages := readMessages(ctx, reader)
 	for {
+		var decoded decodedMessage
+		var ok bool
 		select {
 		case <-ctx.Done():
-			return
-		default:
-			var message json.RawMessage
-			if err := decoder.Decode(&message); err != nil {
-				if errors.Is(err, io.EOF) || ctx.Err() != nil {
-					return
-				}
+			return ctx.Err()
+		case decoded, ok = <-messages:
+		}
+		// the messages are closed once the context is cancelled
+		if !ok {
+			return ctx.Err()
+		}
+
+		message, err := decoded.message, decoded.err
+		if err != nil {
+			// malformed input is answered and skipped, any other error ends the connection
+			var parseErr *protocol.Error
+			if errors.As(err, &parseErr) {
 				x.log.Errorf(ctx, "Error decoding message: %v\n", err)
+				x.sendError(ctx, nil, parseErr)
 				continue
 			}
-			x.eg.Go(func(ctx context.Context) error {
-				defer func() {
-					r := recover()
-					if r != nil {
-						x.log.Errorf(ctx, "[Client][handleMessage] panic: %v, stack:\n%s\n", r, debug.Stack())
-					}
-				}()
-				x.handleMessage(ctx, message)
-				return nil
-			})
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				x.log.Errorf(ctx, "Error reading message: %v\n", err)
+			}
+			return err
 		}
+		x.eg.Go(func(ctx context.Context) error {
+			defer func() {
+				r := recover()
+				if r != nil {
+					x.log.Errorf(ctx, "[Client][handleMessage] panic: %v, stack:\n%s\n", r, debug.Stack())
+				}
+			}()
+			x.handleMessage(ctx, message)
+			return nil
+		})
 	}
 }
 
-func (x *Client) writeLoop(ctx context.Context, writer io.Writer) {
+// writeLoop sends the queued messages until the connection ends, it returns the cause
+func (x *Client) writeLoop(ctx context.Context, writer io.Writer) error {
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return ctx.Err()
 		case req := <-x.writeChan:
 			bs, _ := json.Marshal(req)
 			bs = append(bs, '\n')
 			if _, err := writer.Write(bs); err != nil {
 				x.log.Errorf(ctx, "Error encoding request: %v\n", err)
+				return err
 			}
 		}
 	}
@@ -335,6 +478,80 @@ func (x *Client) registerNotificationHandlers() {
 			return x.options.loggingMessageHandler(ctx, dst)
 		}
 	}
+
+	// Register progress notification handlers
+	if x.options.progressHandler != nil {
+		x.notificationHandlers[protocol.NotificationProgress] = func(ctx context.Context, message json.RawMessage) error {
+			var dst protocol.ProgressNotification
+			if err := json.Unmarshal(message, &dst); err != nil {
+				return fmt.Errorf("failed to unmarshal progress notification: %w", err)
+			}
+			return x.options.progressHandler(ctx, dst)
+		}
+	}
+}
+
+// registerRequestHandlers registers the handlers of the requests the server may send
+func (x *Client) registerRequestHandlers() {
+	x.requestHandlers[protocol.MethodPing] = func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+		return struct{}{}, nil
+	}
+	if x.options.capabilities.Roots != nil {
+		x.requestHandlers[protocol.MethodListRoots] = x.handleListRoots
+	}
+	if x.options.samplingHandler != nil {
+		x.requestHandlers[protocol.MethodCreateMessage] = x.handleCreateMessage
+	}
+	if x.options.elicitationHandler != nil {
+		x.requestHandlers[protocol.MethodElicit] = x.handleElicit
+	}
+}
+
+// keepalive pings the server at the configured interval and closes the connection after too many missed pings
+func (x *Client) keepalive(ctx context.Context) {
+	maxMissed := x.options.keepaliveMaxMissed
+	if maxMissed <= 0 {
+		maxMissed = 1
+	}
+	ticker := time.NewTicker(x.options.keepaliveInterval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, x.options.keepaliveInterval)
+		err := x.Ping(pingCtx)
+		cancel()
+
+		// any response, even an error, proves the server is alive
+		var rpcErr *protocol.Error
+		if err == nil || errors.As(err, &rpcErr) {
+			missed = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		missed++
+		x.log.Warnf(ctx, "Ping missed (%d/%d): %v\n", missed, maxMissed, err)
+		if missed >= maxMissed {
+			if x.options.onKeepaliveTimeout != nil {
+				x.options.onKeepaliveTimeout(ctx, ErrKeepaliveTimeout)
+			}
+			// closing the transport unblocks the read loop
+			x.closeConnection(ErrKeepaliveTimeout)
+			if err := x.transport.Close(); err != nil {
+				x.log.Errorf(ctx, "transport close failed: %v\n", err)
+			}
+			return
+		}
+	}
 }
 
 // initialize sends the initialize request to the server
@@ -373,23 +590,79 @@ func (x *Client) initialize(ctx context.Context) error {
 
 // handleMessage processes a JSON-RPC message
 func (x *Client) handleMessage(ctx context.Context, message json.RawMessage) {
-	// Try to parse as a response
-	var response protocol.JsonrpcResponse
-	x.log.Errorf(ctx, "handleMessage: %s", string(message))
-	if err := json.Unmarshal(message, &response); err == nil && response.ID != nil {
-		x.handleResponse(ctx, &response)
+	// A batch response is processed entry by entry
+	if trimmed := bytes.TrimLeft(message, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		var entries []json.RawMessage
+		if err := json.Unmarshal(message, &entries); err != nil {
+			x.log.Errorf(ctx, "Error decoding batch: %v\n", err)
+			return
+		}
+		for _, entry := range entries {
+			x.handleMessage(ctx, entry)
+		}
+		return
+	}
+
+	x.log.Debugf(ctx, "handleMessage: %s\n", string(message))
+	var pack protocol.JsonrpcPack
+	if err := json.Unmarshal(message, &pack); err != nil {
+		x.log.Errorf(ctx, "Invalid message: %v, message=%s\n", err, string(message))
+		x.sendError(ctx, nil, protocol.NewInvalidRequestError("invalid message: %v", err))
+		return
+	}
+
+	isRequest := pack.Method != "" && len(pack.ID) > 0
+	if pack.Jsonrpc != protocol.JSONRPCVersion {
+		x.log.Errorf(ctx, "Invalid jsonrpc version %q, message=%s\n", pack.Jsonrpc, string(message))
+		invalid := protocol.NewInvalidRequestError("invalid jsonrpc version %q", pack.Jsonrpc)
+		switch {
+		case isRequest:
+			x.sendError(ctx, pack.ID, invalid)
+		case len(pack.ID) > 0:
+			// fail the pending request rather than leaving it waiting
+			x.handleResponse(ctx, protocol.NewJsonrpcResponse(pack.ID, nil, invalid.JsonrpcError()))
+		}
+		return
+	}
+
+	switch {
+	case isRequest:
+		x.handleRequest(ctx, pack.ID, pack.Method, pack.Params)
+	case len(pack.ID) > 0:
+		x.handleResponse(ctx, (*protocol.JsonrpcResponse)(&pack))
+	case pack.Method != "":
+		x.handleNotification(ctx, pack.Method, pack.Params)
+	default:
+		// Unknown message type
+		x.log.Debugf(ctx, "Received unknown message type: %s\n", string(message))
+	}
+}
+
+// handleRequest processes a request from the server and sends back the response
+func (x *Client) handleRequest(ctx context.Context, id json.RawMessage, method protocol.McpMethod, params json.RawMessage) {
+	handler, ok := x.requestHandlers[method]
+	if !ok {
+		x.sendError(ctx, id, protocol.NewMethodNotFoundError("method(%s) not found", method))
 		return
 	}
 
-	// Try to parse as a notification
-	var notification protocol.JsonrpcNotification
-	if err := json.Unmarshal(message, &notification); err == nil && notification.Method != "" {
-		x.handleNotification(ctx, notification.Method, notification.Params)
+	result, err := handler(ctx, params)
+	if err != nil {
+		x.log.Errorf(ctx, "Error handling request %s: %v\n", method, err)
+		var rpcErr *protocol.Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = protocol.NewInternalError("%s", err.Error())
+		}
+		x.sendError(ctx, id, rpcErr)
 		return
 	}
 
-	// Unknown message type
-	x.log.Debugf(ctx, "Received unknown message type: %s\n", string(message))
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		x.sendError(ctx, id, protocol.NewInternalError("failed to marshal result: %v", err))
+		return
+	}
+	x.sendResponse(ctx, protocol.NewJsonrpcResponse(id, resultBytes, nil))
 }
 
 // handleNotification processes a notification from the server
@@ -411,18 +684,17 @@ func (x *Client) handleNotification(ctx context.Context, method protocol.McpMeth
 // handleResponse processes a response from the server
 func (x *Client) handleResponse(ctx context.Context, response *protocol.JsonrpcResponse) {
 	// Find and remove handler for this response
-	var respID int
-	_ = json.Unmarshal(response.ID, &respID)
+	key, valid := requestIDKey(response.ID)
 	x.mu.Lock()
-
-	ch, ok := x.responseHandlers[respID]
+	ch, ok := x.responseHandlers[key]
 	if ok {
-		delete(x.responseHandlers, respID)
+		delete(x.responseHandlers, key)
 	}
 	x.mu.Unlock()
 
-	// If no handler found, ignore response
-	if !ok {
+	// The request was forgotten, or the server answered with an ID it was never sent
+	if !valid || !ok {
+		x.log.Warnf(ctx, "Received response for unknown request %s\n", string(response.ID))
 		return
 	}
 
@@ -434,78 +706,172 @@ func (x *Client) handleResponse(ctx context.Context, response *protocol.JsonrpcR
 	}
 }
 
-// sendRequest sends a request to the server and waits for the response
+// pendingRequest is a request registered for its response
+type pendingRequest struct {
+	key        string
+	request    *protocol.JsonrpcRequest
+	responseCh chan *protocol.JsonrpcResponse
+}
+
+// sendRequest sends a request to the server and waits for the response, an error response is returned as *protocol.Error
 func (x *Client) sendRequest(ctx context.Context, method protocol.McpMethod, params interface{}, result interface{}) error {
+	pending, err := x.newPendingRequest(ctx, method, params)
+	if err != nil {
+		return err
+	}
+
+	// Marshal and send request
+	requestBytes, err := json.Marshal(pending.request)
+	if err != nil {
+		x.forgetPendingRequest(pending)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	select {
+	case x.writeChan <- requestBytes:
+	case <-ctx.Done():
+		x.forgetPendingRequest(pending)
+		return ctx.Err()
+	case <-x.done:
+		x.forgetPendingRequest(pending)
+		return x.Err()
+	}
+
+	return x.awaitResponse(ctx, pending, result)
+}
+
+// newPendingRequest builds a request and registers its response handler
+func (x *Client) newPendingRequest(ctx context.Context, method protocol.McpMethod, params interface{}) (*pendingRequest, error) {
+	if err := x.Err(); err != nil {
+		return nil, err
+	}
+
 	// Generate request ID
-	id := atomic.AddInt64(&x.requestID, 1)
+	var id interface{}
+	if x.options.requestIDGenerator != nil {
+		id = x.options.requestIDGenerator()
+	} else {
+		id = atomic.AddInt64(&x.requestID, 1)
+	}
+	idBs, err := json.Marshal(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request id: %w", err)
+	}
+	key, ok := requestIDKey(idBs)
+	if !ok {
+		return nil, fmt.Errorf("request id %s must be a string or a number", string(idBs))
+	}
 
 	// Marshal params
 	var paramsBytes json.RawMessage
 	if params != nil {
-		var err error
 		paramsBytes, err = json.Marshal(params)
 		if err != nil {
-			return fmt.Errorf("failed to marshal params: %w", err)
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
 		}
 	} else {
 		paramsBytes = json.RawMessage("{}")
 	}
 
-	idBs, _ := json.Marshal(id)
-	// Create JSON-RPC request
-	request := protocol.NewJsonrpcRequest(
-		idBs,
-		method,
-		paramsBytes,
-	)
+	// Attach the _meta carried by the context
+	if meta := RequestMetaFromContext(ctx); len(meta) > 0 {
+		paramsBytes, err = injectMeta(paramsBytes, meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach meta: %w", err)
+		}
+	}
 
-	// Create response channel
-	responseCh := make(chan *protocol.JsonrpcResponse, 1)
+	pending := &pendingRequest{
+		key: key,
+		// Create JSON-RPC request
+		request: protocol.NewJsonrpcRequest(
+			idBs,
+			method,
+			paramsBytes,
+		),
+		// Create response channel
+		responseCh: make(chan *protocol.JsonrpcResponse, 1),
+	}
 
 	// Register response handler
 	x.mu.Lock()
-	x.responseHandlers[int(id)] = responseCh
-	x.mu.Unlock()
-
-	// Marshal and send request
-	requestBytes, err := json.Marshal(request)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+	defer x.mu.Unlock()
+	if _, exists := x.responseHandlers[key]; exists {
+		return nil, fmt.Errorf("request id %s is already waiting for a response", string(idBs))
 	}
+	x.responseHandlers[key] = pending.responseCh
 
-	select {
-	case x.writeChan <- requestBytes:
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+	return pending, nil
+}
 
+// forgetPendingRequest removes the response handler of a request
+func (x *Client) forgetPendingRequest(pending *pendingRequest) {
+	x.mu.Lock()
+	delete(x.responseHandlers, pending.key)
+	x.mu.Unlock()
+}
+
+// awaitResponse waits for the response of a sent request and decodes its result
+func (x *Client) awaitResponse(ctx context.Context, pending *pendingRequest, result interface{}) error {
 	// Wait for response or context cancellation
 	select {
-	case response := <-responseCh:
-		// Process response
-		if response.Error != nil {
-			return fmt.Errorf("server error: %s (code: %d)", response.Error.Message, response.Error.Code)
-		}
+	case response := <-pending.responseCh:
+		return x.processResponse(response, result)
 
-		// Unmarshal result
-		if result != nil && response.Result != nil {
-			if err := json.Unmarshal(response.Result, result); err != nil {
-				return fmt.Errorf("failed to unmarshal result: %w", err)
-			}
+	case <-x.done:
+		// The connection is closed, unless the response arrived meanwhile
+		select {
+		case response := <-pending.responseCh:
+			return x.processResponse(response, result)
+		default:
 		}
-
-		return nil
+		x.forgetPendingRequest(pending)
+		return x.Err()
 
 	case <-ctx.Done():
 		// Context canceled
-		x.mu.Lock()
-		delete(x.responseHandlers, int(id))
-		x.mu.Unlock()
+		x.forgetPendingRequest(pending)
+
+		// Tell the server to stop processing, the initialize request must not be cancelled
+		if pending.request.Method != protocol.MethodInitialize {
+			x.notifyCancelled(ctx, pending.request.ID, ctx.Err())
+		}
 
 		return ctx.Err()
 	}
 }
 
+// processResponse returns the error of a response, or unmarshals its result into result
+func (x *Client) processResponse(response *protocol.JsonrpcResponse, result interface{}) error {
+	if response.Error != nil {
+		return protocol.NewErrorFromJsonrpc(response.Error)
+	}
+
+	// Unmarshal result
+	if result != nil && response.Result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// notifyCancelled tells the server that the client is no longer interested in the response of a request
+func (x *Client) notifyCancelled(ctx context.Context, id json.RawMessage, reason error) {
+	// The request context is already done, send the notification with a detached one
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelNotificationTimeout)
+	defer cancel()
+
+	err := x.sendNotification(ctx, protocol.NotificationCancelled, protocol.CancelledNotification{
+		RequestID: id,
+		Reason:    reason.Error(),
+	})
+	if err != nil {
+		x.log.Warnf(ctx, "Failed to notify cancellation of request %s: %v\n", string(id), err)
+	}
+}
+
 // sendNotification sends a notification to the server
 func (x *Client) sendNotification(ctx context.Context, method protocol.McpMethod, params interface{}) error {
 	// Marshal params
@@ -531,21 +897,44 @@ func (x *Client) sendNotification(ctx context.Context, method protocol.McpMethod
 	case x.writeChan <- notificationBytes:
 	case <-ctx.Done():
 		return fmt.Errorf("failed to send notification: %w", ctx.Err())
+	case <-x.done:
+		return x.Err()
 	}
 
 	return nil
 }
 
+// sendError sends an error response to the server, a nil id is sent as null
+func (x *Client) sendError(ctx context.Context, id json.RawMessage, rpcErr *protocol.Error) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	x.sendResponse(ctx, protocol.NewJsonrpcResponse(id, nil, rpcErr.JsonrpcError()))
+}
+
+// sendResponse sends a response to a request of the server
+func (x *Client) sendResponse(ctx context.Context, response *protocol.JsonrpcResponse) {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		x.log.Errorf(ctx, "Failed to marshal response: %v\n", err)
+		return
+	}
+
+	select {
+	case x.writeChan <- responseBytes:
+	case <-ctx.Done():
+	case <-x.done:
+	}
+}
+
 // Close terminates the client connection
 func (x *Client) Close() error {
-	x.mu.Lock()
-	if x.cancel != nil {
-		x.cancel()
-	}
-	x.mu.Unlock()
+	x.closeConnection(ErrClientClosed)
 
 	// Wait for message processor to finish
-	_ = x.eg.Wait()
+	if x.eg != nil {
+		_ = x.eg.Wait()
+	}
 
 	// Close transport
 	if err := x.transport.Close(); err != nil {
@@ -575,6 +964,11 @@ func (x *Client) IsInitialized() bool {
 	return x.initialized
 }
 
+// Ping checks that the server is alive
+func (x *Client) Ping(ctx context.Context) error {
+	return x.sendRequest(ctx, protocol.MethodPing, nil, nil)
+}
+
 // ListTools retrieves the list of available tools from the server
 func (x *Client) ListTools(ctx context.Context) (protocol.ListToolsResult, error) {
 	var result protocol.ListToolsResult
@@ -645,7 +1039,25 @@ func (x *Client) GetPrompt(ctx context.Context, request protocol.GetPromptReques
 	return result, nil
 }
 
+// SetLevel asks the server to send the log messages of the given level and above
+func (x *Client) SetLevel(ctx context.Context, level protocol.LoggingLevel) error {
+	return x.sendRequest(ctx, protocol.MethodSetLevel, protocol.SetLevelRequest{Level: level}, nil)
+}
+
+// Complete asks the server for the completion options of a prompt argument or a resource template variable
+func (x *Client) Complete(ctx context.Context, request protocol.CompleteRequest) (protocol.CompleteResult, error) {
+	var result protocol.CompleteResult
+	err := x.sendRequest(ctx, protocol.MethodComplete, request, &result)
+	if err != nil {
+		return protocol.CompleteResult{}, err
+	}
+	return result, nil
+}
+
 // ListRoots retrieves the list of available roots from the client
+//
+// Deprecated: roots are listed by the server, not the client. Use WithRoots or WithRootsProvider to answer
+// the roots/list requests of the server, servers answer this request with a method not found error.
 func (x *Client) ListRoots(ctx context.Context) (protocol.ListRootsResult, error) {
 	var result protocol.ListRootsResult
 	err := x.sendRequest(ctx, protocol.MethodListRoots, nil, &result)
@@ -656,6 +1068,9 @@ func (x *Client) ListRoots(ctx context.Context) (protocol.ListRootsResult, error
 }
 
 // CreateMessage requests a completion from the client's LLM
+//
+// Deprecated: sampling is requested by the server, not the client. Use WithSamplingHandler to answer the
+// sampling requests of the server, servers answer this request with a method not found error.
 func (x *Client) CreateMessage(ctx context.Context, request protocol.CreateMessageRequest) (protocol.CreateMessageResult, error) {
 	var result protocol.CreateMessageResult
 	err := x.sendRequest(ctx, protocol.MethodCreateMessage, request, &result)
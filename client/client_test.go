@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mcp4go/mcp4go/client/transport"
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// 通过管道模拟的服务端
+type fakeServer struct {
+	decoder *json.Decoder
+	reader  *io.PipeReader
+	writer  *io.PipeWriter
+}
+
+// read 读取客户端发送的下一条消息
+func (x *fakeServer) read() (protocol.JsonrpcPack, error) {
+	var pack protocol.JsonrpcPack
+	err := x.decoder.Decode(&pack)
+	return pack, err
+}
+
+// respond 以原样的 id 回复客户端
+func (x *fakeServer) respond(id string, result string) error {
+	_, err := x.writer.Write([]byte(`{"jsonrpc":"2.0","id":` + id + `,"result":` + result + "}\n"))
+	return err
+}
+
+// close 关闭服务端的连接
+func (x *fakeServer) close() {
+	_ = x.writer.Close()
+	_ = x.reader.Close()
+}
+
+// connectFakeServer 创建连接到模拟服务端的客户端并完成初始化
+func connectFakeServer(t *testing.T, opts ...Option) (*Client, *fakeServer) {
+	t.Helper()
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+	server := &fakeServer{
+		decoder: json.NewDecoder(serverReader),
+		reader:  serverReader,
+		writer:  serverWriter,
+	}
+
+	cli, _, err := NewClient(transport.NewMemoryClientTransport(clientReader, clientWriter), opts...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() {
+		server.close()
+		_ = cli.Close()
+	})
+
+	initDone := make(chan error, 1)
+	go func() {
+		req, err := server.read()
+		if err != nil {
+			initDone <- err
+			return
+		}
+		err = server.respond(string(req.ID), `{"protocolVersion":"2024-11-05","capabilities":{},"serverInfo":{"name":"fake","version":"0.1.0"}}`)
+		if err != nil {
+			initDone <- err
+			return
+		}
+		// 读取 initialized 通知
+		_, err = server.read()
+		initDone <- err
+	}()
+
+	// 连接的生命周期与测试一致
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := cli.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	select {
+	case err := <-initDone:
+		if err != nil {
+			t.Fatalf("Initialization failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Initialization timed out")
+	}
+	return cli, server
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// ErrConnectionClosed is matched with errors.Is by the errors of the requests that failed because the connection
+// to the server is closed, the cause is available through *ConnectionClosedError
+var ErrConnectionClosed = errors.New("connection closed")
+
+// ErrClientClosed is the cause of the connection closed by Close
+var ErrClientClosed = errors.New("client closed")
+
+// ConnectionClosedError reports that the connection to the server is closed and why
+type ConnectionClosedError struct {
+	// Cause is the error that ended the connection, io.EOF when the server closed it
+	Cause error
+}
+
+func (x *ConnectionClosedError) Error() string {
+	return fmt.Sprintf("connection closed: %v", x.Cause)
+}
+
+func (x *ConnectionClosedError) Unwrap() error {
+	return x.Cause
+}
+
+func (x *ConnectionClosedError) Is(target error) bool {
+	return target == ErrConnectionClosed
+}
+
+// Done returns a channel closed when the connection to the server is closed
+func (x *Client) Done() <-chan struct{} {
+	return x.done
+}
+
+// Err returns a *ConnectionClosedError once the connection to the server is closed, nil before
+func (x *Client) Err() error {
+	select {
+	case <-x.done:
+		return x.closeErr
+	default:
+		return nil
+	}
+}
+
+// closeConnection marks the connection as closed, the pending requests and the following ones fail with
+// a *ConnectionClosedError carrying the cause of the first call
+func (x *Client) closeConnection(cause error) {
+	x.closeOnce.Do(func() {
+		if cause == nil {
+			cause = io.EOF
+		}
+		x.closeErr = &ConnectionClosedError{Cause: cause}
+
+		x.mu.Lock()
+		x.responseHandlers = make(map[string]chan *protocol.JsonrpcResponse)
+		if x.cancel != nil {
+			x.cancel()
+		}
+		x.mu.Unlock()
+
+		// wakes up the requests waiting for their response
+		close(x.done)
+	})
+}
+
+type decodedMessage struct {
+	message json.RawMessage
+	err     error
+}
+
+// readMessages decodes the messages of the reader in the background, so that the read loop can end
+// while a read is blocked, the channel is closed after the first error that is not a parse error
+func readMessages(ctx context.Context, reader io.Reader) <-chan decodedMessage {
+	messages := make(chan decodedMessage)
+	go func() {
+		defer close(messages)
+		decoder := protocol.NewMessageDecoder(reader)
+		for {
+			message, err := decoder.Decode()
+			select {
+			case messages <- decodedMessage{message: message, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			var parseErr *protocol.Error
+			if err != nil && !errors.As(err, &parseErr) {
+				return
+			}
+		}
+	}()
+	return messages
+}
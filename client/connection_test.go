@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// 测试服务端断开连接时，等待中的请求与后续请求都以 ErrConnectionClosed 失败
+func TestConnectionClosedByServer(t *testing.T) {
+	cli, server := connectFakeServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := cli.ListTools(ctx)
+		errCh <- err
+	}()
+
+	// 收到请求后不回复，直接断开
+	if _, err := server.read(); err != nil {
+		t.Fatalf("Failed to read request: %v", err)
+	}
+	if cli.Err() != nil {
+		t.Fatalf("Unexpected error before the connection is closed: %v", cli.Err())
+	}
+	_ = server.writer.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrConnectionClosed) || !errors.Is(err, io.EOF) {
+			t.Errorf("Expected the pending request to fail with EOF, got: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Pending request did not fail")
+	}
+
+	select {
+	case <-cli.Done():
+	case <-ctx.Done():
+		t.Fatal("Done was not closed")
+	}
+	var closedErr *ConnectionClosedError
+	if err := cli.Err(); !errors.As(err, &closedErr) || closedErr.Cause != io.EOF {
+		t.Errorf("Unexpected connection error: %v", err)
+	}
+
+	// 后续请求立即失败
+	start := time.Now()
+	if err := cli.Ping(ctx); !errors.Is(err, ErrConnectionClosed) {
+		t.Errorf("Expected the request to fail fast, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Request took %s to fail", elapsed)
+	}
+}
+
+// 测试 Close 关闭连接后的错误原因
+func TestConnectionClosedByClient(t *testing.T) {
+	cli, _ := connectFakeServer(t)
+
+	if err := cli.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	select {
+	case <-cli.Done():
+	default:
+		t.Fatal("Done was not closed")
+	}
+	if err := cli.Err(); !errors.Is(err, ErrConnectionClosed) || !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Unexpected connection error: %v", err)
+	}
+	if err := cli.Ping(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected the request to fail, got: %v", err)
+	}
+}
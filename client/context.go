@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+type requestMetaKey struct{}
+
+// ContextWithRequestMeta returns a context whose requests carry the given _meta (progress tokens, trace context, custom keys)
+func ContextWithRequestMeta(ctx context.Context, meta json.RawMessage) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// ContextWithProgressToken returns a context whose requests ask the server for progress notifications with the given token
+func ContextWithProgressToken(ctx context.Context, token protocol.ProgressToken) context.Context {
+	meta, _ := json.Marshal(protocol.RequestMeta{ProgressToken: token})
+	return ContextWithRequestMeta(ctx, meta)
+}
+
+// RequestMetaFromContext returns the _meta attached to the context, nil if none
+func RequestMetaFromContext(ctx context.Context) json.RawMessage {
+	meta, _ := ctx.Value(requestMetaKey{}).(json.RawMessage)
+	return meta
+}
+
+// injectMeta sets the _meta field of the request params
+func injectMeta(params json.RawMessage, meta json.RawMessage) (json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(params) > 0 && string(params) != "null" {
+		if err := json.Unmarshal(params, &fields); err != nil {
+			return nil, fmt.Errorf("params must be an object to carry _meta: %w", err)
+		}
+	}
+	fields["_meta"] = meta
+	return json.Marshal(fields)
+}
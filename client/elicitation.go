@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+	"github.com/mcp4go/mcp4go/protocol/jsonschema"
+)
+
+// ElicitationHandler asks the user for the input described by the request and returns the answer
+// The content of an accept result must match request.RequestedSchema, it is dropped for decline and cancel
+type ElicitationHandler func(ctx context.Context, request protocol.ElicitRequest) (protocol.ElicitResult, error)
+
+// handleElicit answers an elicitation/create request with the configured ElicitationHandler
+func (x *Client) handleElicit(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var request protocol.ElicitRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, protocol.NewInvalidParamsError("invalid params: %w", err)
+	}
+	if request.RequestedSchema.Type != jsonschema.Object {
+		return nil, protocol.NewInvalidParamsError("requested schema must be an object, got %q", request.RequestedSchema.Type)
+	}
+
+	result, err := x.options.elicitationHandler(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("elicitation handler failed: %w", err)
+	}
+
+	switch result.Action {
+	case protocol.ElicitActionAccept:
+		var content interface{}
+		if err := json.Unmarshal(result.Content, &content); err != nil {
+			return nil, fmt.Errorf("invalid elicitation content: %w", err)
+		}
+		if !jsonschema.Validate(request.RequestedSchema, content) {
+			return nil, fmt.Errorf("elicitation content does not match the requested schema")
+		}
+	case protocol.ElicitActionDecline, protocol.ElicitActionCancel:
+		result.Content = nil
+	default:
+		return nil, fmt.Errorf("unknown elicitation action %q", result.Action)
+	}
+	return result, nil
+}
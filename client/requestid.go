@@ -0,0 +1,39 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// RequestIDGenerator returns the ID of the next request sent to the server, a string or a number
+// IDs must be unique among the requests waiting for their response
+type RequestIDGenerator func() interface{}
+
+// requestIDKey returns the canonical JSON of a request ID, so that the response of a request is matched
+// whatever the formatting of its ID. ok is false if the ID is neither a string nor a number
+func requestIDKey(id json.RawMessage) (key string, ok bool) {
+	decoder := json.NewDecoder(bytes.NewReader(id))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", false
+	}
+
+	switch v := v.(type) {
+	case string:
+		bs, _ := json.Marshal(v)
+		return string(bs), true
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return strconv.FormatInt(i, 10), true
+		}
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestIDKey(t *testing.T) {
+	tests := []struct {
+		id  string
+		key string
+		ok  bool
+	}{
+		{id: `1`, key: `1`, ok: true},
+		{id: `1.0`, key: `1`, ok: true},
+		{id: `1e0`, key: `1`, ok: true},
+		{id: ` 1 `, key: `1`, ok: true},
+		{id: `1.5`, key: `1.5`, ok: true},
+		{id: `"1"`, key: `"1"`, ok: true},
+		{id: `"\u0031"`, key: `"1"`, ok: true},
+		{id: `null`, ok: false},
+		{id: `{"id":1}`, ok: false},
+		{id: `[1]`, ok: false},
+		{id: `true`, ok: false},
+		{id: ``, ok: false},
+	}
+	for _, tt := range tests {
+		key, ok := requestIDKey(json.RawMessage(tt.id))
+		if key != tt.key || ok != tt.ok {
+			t.Errorf("requestIDKey(%s) = %q, %v, want %q, %v", tt.id, key, ok, tt.key, tt.ok)
+		}
+	}
+}
+
+// 测试字符串 id 的请求能匹配到以不同格式回复的响应
+func TestRequestIDGeneratorRoundTrip(t *testing.T) {
+	var next int64
+	cli, server := connectFakeServer(t, WithRequestIDGenerator(func() interface{} {
+		return "req-" + strconv.FormatInt(atomic.AddInt64(&next, 1), 10)
+	}))
+
+	serverErr := make(chan error, 1)
+	go func() {
+		req, err := server.read()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		if string(req.ID) != `"req-2"` {
+			t.Errorf("Unexpected request id: %s", req.ID)
+		}
+		// 以转义后的形式回复同一个 id
+		serverErr <- server.respond(`"req\u002d2"`, `{"tools":[{"name":"echo","inputSchema":{"type":"object"}}]}`)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := cli.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "echo" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if err := <-serverErr; err != nil {
+		t.Fatalf("Server failed: %v", err)
+	}
+}
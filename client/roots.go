@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// RootsProvider returns the roots exposed to the server when it lists them
+type RootsProvider func(ctx context.Context) ([]protocol.Root, error)
+
+// SetRoots replaces the roots exposed to the server and notifies it when the roots capability was enabled
+// with WithRootsCapability(true), the notification is skipped before the client is initialized
+func (x *Client) SetRoots(ctx context.Context, roots ...protocol.Root) error {
+	x.rootsMu.Lock()
+	x.roots = append([]protocol.Root(nil), roots...)
+	x.rootsMu.Unlock()
+
+	return x.NotifyRootsListChanged(ctx)
+}
+
+// NotifyRootsListChanged tells the server the roots changed, hosts using a RootsProvider call it when the
+// provided roots change. It does nothing unless WithRootsCapability(true) was set and the client is initialized
+func (x *Client) NotifyRootsListChanged(ctx context.Context) error {
+	caps := x.options.capabilities.Roots
+	if caps == nil || !caps.ListChanged || !x.IsInitialized() {
+		return nil
+	}
+	if err := x.sendNotification(ctx, protocol.NotificationRootsListChanged, protocol.RootsListChangedNotification{}); err != nil {
+		return fmt.Errorf("roots list changed notification failed: %w", err)
+	}
+	return nil
+}
+
+// handleListRoots answers a roots/list request with the configured RootsProvider or the current roots
+func (x *Client) handleListRoots(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+	var roots []protocol.Root
+	if x.options.rootsProvider != nil {
+		var err error
+		roots, err = x.options.rootsProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("roots provider failed: %w", err)
+		}
+	} else {
+		x.rootsMu.RLock()
+		roots = append([]protocol.Root(nil), x.roots...)
+		x.rootsMu.RUnlock()
+	}
+	if roots == nil {
+		roots = []protocol.Root{}
+	}
+	return protocol.ListRootsResult{Roots: roots}, nil
+}
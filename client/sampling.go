@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// SamplingHandler samples the language model of the host for a sampling/createMessage request of the server
+type SamplingHandler func(ctx context.Context, request protocol.CreateMessageRequest) (protocol.CreateMessageResult, error)
+
+// SamplingApproval reviews the sampling requests of the server, returning an error rejects the request
+// and the server receives a request failed error instead of a result
+type SamplingApproval struct {
+	// Request reviews, and may edit, a request before it is sampled, nil approves every request
+	Request func(ctx context.Context, request protocol.CreateMessageRequest) (protocol.CreateMessageRequest, error)
+	// Result reviews, and may edit, a result before it is released to the server, nil approves every result
+	Result func(ctx context.Context, request protocol.CreateMessageRequest, result protocol.CreateMessageResult) (protocol.CreateMessageResult, error)
+}
+
+// handleCreateMessage answers a sampling/createMessage request with the configured SamplingHandler
+func (x *Client) handleCreateMessage(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var request protocol.CreateMessageRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, protocol.NewInvalidParamsError("invalid params: %w", err)
+	}
+
+	approval := x.options.samplingApproval
+	if approval.Request != nil {
+		var err error
+		request, err = approval.Request(ctx, request)
+		if err != nil {
+			return nil, protocol.NewRequestFailedError("sampling request rejected: %w", err)
+		}
+	}
+
+	result, err := x.options.samplingHandler(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("sampling handler failed: %w", err)
+	}
+
+	if approval.Result != nil {
+		result, err = approval.Result(ctx, request, result)
+		if err != nil {
+			return nil, protocol.NewRequestFailedError("sampling result rejected: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -3,12 +3,38 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mcp4go/mcp4go/protocol"
 )
 
+// DisplayName returns the name to show to users, falling back from the title (and the annotations title for tools) to the name
+func DisplayName[T protocol.Tool | protocol.Prompt | protocol.PromptArgument | protocol.Resource | protocol.ResourceTemplate](v T) string {
+	var name, title string
+	switch item := any(v).(type) {
+	case protocol.Tool:
+		name, title = item.Name, item.Title
+		if title == "" && item.Annotations != nil {
+			title = item.Annotations.Title
+		}
+	case protocol.Prompt:
+		name, title = item.Name, item.Title
+	case protocol.PromptArgument:
+		name, title = item.Name, item.Title
+	case protocol.Resource:
+		name, title = item.Name, item.Title
+	case protocol.ResourceTemplate:
+		name, title = item.Name, item.Title
+	}
+	if title != "" {
+		return title
+	}
+	return name
+}
+
 // ToolHelper provides convenient methods for calling tools
 type ToolHelper struct {
 	client *Client
@@ -57,6 +83,105 @@ func (h *ToolHelper) CallWithTimeout(name string, args interface{}, timeout time
 	return h.client.CallTool(ctx, request)
 }
 
+// ToolExecutionError is a tool failure reported in a result with isError set,
+// protocol errors such as an unknown tool or invalid arguments are returned as *protocol.Error instead
+type ToolExecutionError struct {
+	// Name of the tool that failed
+	Name string
+	// Content describing the failure
+	Content []protocol.Content
+}
+
+// Error joins the text contents of the failure
+func (x *ToolExecutionError) Error() string {
+	texts := make([]string, 0, len(x.Content))
+	for _, content := range x.Content {
+		if content.Type == protocol.ContentTypeText {
+			texts = append(texts, content.Text)
+		}
+	}
+	return fmt.Sprintf("tool %s failed: %s", x.Name, strings.Join(texts, "\n"))
+}
+
+// ToolResultError returns a *ToolExecutionError if the result reports a tool failure, nil otherwise
+func ToolResultError(name string, result protocol.CallToolResult) error {
+	if !result.IsError {
+		return nil
+	}
+	return &ToolExecutionError{
+		Name:    name,
+		Content: result.Content,
+	}
+}
+
+// IsToolExecutionError reports whether the error is a tool failure reported in the result
+func IsToolExecutionError(err error) bool {
+	var toolErr *ToolExecutionError
+	return errors.As(err, &toolErr)
+}
+
+// IsProtocolError reports whether the error is a JSON-RPC error returned by the server
+func IsProtocolError(err error) bool {
+	var rpcErr *protocol.Error
+	return errors.As(err, &rpcErr)
+}
+
+// CallChecked executes a tool with JSON-encoded arguments, a tool failure is returned as *ToolExecutionError
+func (h *ToolHelper) CallChecked(ctx context.Context, name string, args interface{}) (protocol.CallToolResult, error) {
+	result, err := h.CallWithJSON(ctx, name, args)
+	if err != nil {
+		return result, err
+	}
+	return result, ToolResultError(name, result)
+}
+
+// DecodeStructuredContent decodes the structured content of a tool result into the given type
+func DecodeStructuredContent[T any](result protocol.CallToolResult) (T, error) {
+	var out T
+	if len(result.StructuredContent) == 0 {
+		return out, errors.New("tool result has no structured content")
+	}
+	if err := json.Unmarshal(result.StructuredContent, &out); err != nil {
+		return out, fmt.Errorf("failed to decode structured content: %w", err)
+	}
+	return out, nil
+}
+
+// CallStructured executes a tool with JSON-encoded arguments and decodes its structured content into the given type
+func CallStructured[T any](ctx context.Context, h *ToolHelper, name string, args interface{}) (T, error) {
+	result, err := h.CallChecked(ctx, name, args)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return DecodeStructuredContent[T](result)
+}
+
+// GetTool finds the definition of a tool by its name
+func (h *ToolHelper) GetTool(ctx context.Context, name string) (protocol.Tool, error) {
+	result, err := h.client.ListTools(ctx)
+	if err != nil {
+		return protocol.Tool{}, err
+	}
+
+	for _, tool := range result.Tools {
+		if tool.Name == name {
+			return tool, nil
+		}
+	}
+
+	return protocol.Tool{}, fmt.Errorf("tool not found: %s", name)
+}
+
+// GetAnnotations returns the behaviour hints of a tool, nil if the server did not provide any
+func (h *ToolHelper) GetAnnotations(ctx context.Context, name string) (*protocol.ToolAnnotations, error) {
+	tool, err := h.GetTool(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return tool.Annotations, nil
+}
+
 // ResourceHelper provides convenient methods for working with resources
 type ResourceHelper struct {
 	client *Client
@@ -95,6 +220,25 @@ func (h *ResourceHelper) ReadTextContent(ctx context.Context, uri string) (strin
 	return "", fmt.Errorf("no text content found for resource: %s", uri)
 }
 
+// ResolveLinks reads every resource linked from a tool result and returns their contents
+func (h *ResourceHelper) ResolveLinks(ctx context.Context, result protocol.CallToolResult) ([]protocol.ResourceContent, error) {
+	var contents []protocol.ResourceContent
+	for _, content := range result.Content {
+		link, ok := content.ResourceLink()
+		if !ok {
+			continue
+		}
+
+		resource, err := h.ReadByURI(ctx, link.URI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve resource link %s: %w", link.URI, err)
+		}
+		contents = append(contents, resource.Contents...)
+	}
+
+	return contents, nil
+}
+
 // PromptHelper provides convenient methods for working with prompts
 type PromptHelper struct {
 	client *Client
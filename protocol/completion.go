@@ -0,0 +1,102 @@
+package protocol
+
+import "encoding/json"
+
+// Types of the reference a completion request is about
+// 补全请求所引用对象的类型
+const (
+	// CompletionRefPrompt references a prompt by name
+	// CompletionRefPrompt 通过名称引用提示
+	CompletionRefPrompt = "ref/prompt"
+	// CompletionRefResource references a resource template by URI
+	// CompletionRefResource 通过 URI 引用资源模板
+	CompletionRefResource = "ref/resource"
+)
+
+// CompletionMaxValues is the maximum number of values in a completion result
+// CompletionMaxValues 是补全结果中值的最大数量
+const CompletionMaxValues = 100
+
+// CompletionReference identifies the prompt or resource template whose argument is completed
+// CompletionReference 标识需要补全参数的提示或资源模板
+type CompletionReference struct {
+	// Type of the reference, ref/prompt or ref/resource
+	// 引用类型，ref/prompt 或 ref/resource
+	Type string `json:"type"`
+	// The name of the prompt, for ref/prompt
+	// 提示的名称，用于 ref/prompt
+	Name string `json:"name,omitempty"`
+	// The URI or URI template of the resource, for ref/resource
+	// 资源的 URI 或 URI 模板，用于 ref/resource
+	URI string `json:"uri,omitempty"`
+}
+
+// NewPromptReference creates a reference to a prompt
+// NewPromptReference 创建对提示的引用
+func NewPromptReference(name string) CompletionReference {
+	return CompletionReference{Type: CompletionRefPrompt, Name: name}
+}
+
+// NewResourceReference creates a reference to a resource template
+// NewResourceReference 创建对资源模板的引用
+func NewResourceReference(uri string) CompletionReference {
+	return CompletionReference{Type: CompletionRefResource, URI: uri}
+}
+
+// CompletionArgument is the argument being completed
+// CompletionArgument 是正在补全的参数
+type CompletionArgument struct {
+	// The name of the argument, or of the URI template variable
+	// 参数的名称，或 URI 模板变量的名称
+	Name string `json:"name"`
+	// The value of the argument typed so far
+	// 目前已输入的参数值
+	Value string `json:"value"`
+}
+
+// CompletionContext holds additional context for a completion request
+// CompletionContext 保存补全请求的附加上下文
+type CompletionContext struct {
+	// Previously resolved arguments of the prompt or URI template
+	// 提示或 URI 模板中已解析的参数
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// CompleteRequest is sent from the client to ask the server for completion options
+// CompleteRequest 是客户端发送给服务器以请求补全选项的请求
+type CompleteRequest struct {
+	// The prompt or resource template being completed
+	// 正在补全的提示或资源模板
+	Ref CompletionReference `json:"ref"`
+	// The argument being completed
+	// 正在补全的参数
+	Argument CompletionArgument `json:"argument"`
+	// Additional context for the completion
+	// 补全的附加上下文
+	Context *CompletionContext `json:"context,omitempty"`
+}
+
+// Completion holds the completion options
+// Completion 保存补全选项
+type Completion struct {
+	// Completion values, at most CompletionMaxValues
+	// 补全值，最多 CompletionMaxValues 个
+	Values []string `json:"values"`
+	// The total number of completion options available, it can exceed the number of values
+	// 可用补全选项的总数，可以超过值的数量
+	Total int `json:"total,omitempty"`
+	// Whether there are additional completion options beyond those provided
+	// 除已提供的值之外是否还有更多补全选项
+	HasMore bool `json:"hasMore,omitempty"`
+}
+
+// CompleteResult is the server's response to a completion/complete request
+// CompleteResult 是服务器对 completion/complete 请求的响应
+type CompleteResult struct {
+	// The completion options
+	// 补全选项
+	Completion Completion `json:"completion"`
+	// Reserved by MCP for additional metadata
+	// 保留给MCP用于附加元数据
+	Meta json.RawMessage `json:"_meta,omitempty"`
+}
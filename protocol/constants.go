@@ -65,6 +65,9 @@ const (
 	// Sampling methods
 	MethodCreateMessage McpMethod = "sampling/createMessage"
 
+	// Elicitation methods
+	MethodElicit McpMethod = "elicitation/create"
+
 	// Logging methods
 	MethodSetLevel McpMethod = "logging/setLevel"
 )
@@ -73,7 +76,7 @@ const (
 const (
 	// Lifecycle notifications
 	NotificationInitialized = "notifications/initialized" // Notification after initialization
-	NotificationCancelled   = "notifications/canceled"    // Notification for cancellation
+	NotificationCancelled   = "notifications/cancelled"   // Notification for cancellation
 	NotificationProgress    = "notifications/progress"    // Notification for progress updates
 
 	// List changed notifications
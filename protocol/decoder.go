@@ -0,0 +1,89 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MessageDecoder reads JSON-RPC messages from a stream and resynchronizes on the next line after malformed input
+// MessageDecoder 从流中读取 JSON-RPC 消息，遇到格式错误的输入时在下一行重新同步
+type MessageDecoder struct {
+	reader  *resyncReader
+	decoder *json.Decoder
+}
+
+// NewMessageDecoder creates a decoder reading from r
+// NewMessageDecoder 创建一个从 r 读取的解码器
+func NewMessageDecoder(r io.Reader) *MessageDecoder {
+	reader := &resyncReader{br: bufio.NewReader(r)}
+	return &MessageDecoder{
+		reader:  reader,
+		decoder: json.NewDecoder(reader),
+	}
+}
+
+// Decode reads the next message
+// Decode 读取下一条消息
+//
+// Malformed input is skipped up to the end of its line and reported as a parse error (*Error), after which decoding
+// can continue. Any other error, including io.EOF, means the stream cannot be read anymore
+// 格式错误的输入会被跳过到行尾并作为解析错误（*Error）返回，之后可以继续解码。其他错误（包括 io.EOF）表示流已无法继续读取
+func (x *MessageDecoder) Decode() (json.RawMessage, error) {
+	var message json.RawMessage
+	err := x.decoder.Decode(&message)
+	if err == nil {
+		return message, nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			// the stream ended in the middle of a message
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	// drop the rest of the malformed line and restart decoding from the next one
+	buffered, _ := io.ReadAll(x.decoder.Buffered())
+	if err := x.reader.skipLine(buffered); err != nil {
+		return nil, err
+	}
+	x.decoder = json.NewDecoder(x.reader)
+	return nil, NewParseError("parse error: %w", err)
+}
+
+// resyncReader reads pending bytes returned by a decoder before the underlying reader
+type resyncReader struct {
+	pending []byte
+	br      *bufio.Reader
+}
+
+func (x *resyncReader) Read(p []byte) (int, error) {
+	if len(x.pending) > 0 {
+		n := copy(p, x.pending)
+		x.pending = x.pending[n:]
+		return n, nil
+	}
+	return x.br.Read(p)
+}
+
+// skipLine puts back the bytes buffered by a decoder and discards everything up to the next newline
+func (x *resyncReader) skipLine(buffered []byte) error {
+	// the buffered bytes start before the malformed message, possibly with the newline ending the previous one
+	x.pending = bytes.TrimLeft(append(buffered, x.pending...), " \t\r\n")
+	if i := bytes.IndexByte(x.pending, '\n'); i >= 0 {
+		x.pending = x.pending[i+1:]
+		return nil
+	}
+	x.pending = nil
+	// at the end of the stream the next read reports io.EOF again
+	if _, err := x.br.ReadBytes('\n'); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("skip malformed message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package protocol
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMessageDecoder(t *testing.T) {
+	decoder := NewMessageDecoder(strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" +
+			`{"jsonrpc":"2.0",broken}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"ping"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	))
+
+	message, err := decoder.Decode()
+	if err != nil || !strings.Contains(string(message), `"id":1`) {
+		t.Fatalf("unexpected first message: %s %v", message, err)
+	}
+
+	_, err = decoder.Decode()
+	if code, _ := ErrorCode(err); code != ErrorCodeParseError {
+		t.Fatalf("expected parse error, got: %v", err)
+	}
+
+	// 解析错误之后从下一行继续，最后一帧没有换行符
+	for _, id := range []string{`"id":2`, `"id":3`} {
+		message, err = decoder.Decode()
+		if err != nil || !strings.Contains(string(message), id) {
+			t.Fatalf("unexpected message: %s %v", message, err)
+		}
+	}
+
+	if _, err = decoder.Decode(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+}
+
+func TestJsonrpcRequestValidate(t *testing.T) {
+	if err := NewJsonrpcRequest([]byte("1"), MethodPing, nil).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	req := &JsonrpcRequest{Jsonrpc: "1.0", Method: MethodPing}
+	if code, _ := ErrorCode(req.Validate()); code != ErrorCodeInvalidRequest {
+		t.Errorf("expected invalid request for wrong version")
+	}
+	req = &JsonrpcRequest{Jsonrpc: JSONRPCVersion}
+	if code, _ := ErrorCode(req.Validate()); code != ErrorCodeInvalidRequest {
+		t.Errorf("expected invalid request for missing method")
+	}
+}
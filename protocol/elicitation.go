@@ -0,0 +1,50 @@
+package protocol
+
+import (
+	"encoding/json"
+
+	"github.com/mcp4go/mcp4go/protocol/jsonschema"
+)
+
+// ElicitAction is the answer of the user to an elicitation request
+// ElicitAction 是用户对信息征询请求的回答
+type ElicitAction string
+
+// Elicitation actions
+// 信息征询的回答
+const (
+	// The user submitted the requested input
+	// 用户提交了请求的输入
+	ElicitActionAccept ElicitAction = "accept"
+	// The user explicitly refused to provide the input
+	// 用户明确拒绝提供输入
+	ElicitActionDecline ElicitAction = "decline"
+	// The user dismissed the request without choosing
+	// 用户未做选择便关闭了请求
+	ElicitActionCancel ElicitAction = "cancel"
+)
+
+// ElicitRequest is sent from the server to request structured input from the user through the client
+// ElicitRequest 是从服务器发送到客户端的请求，通过客户端向用户请求结构化输入
+type ElicitRequest struct {
+	// The message presented to the user
+	// 展示给用户的消息
+	Message string `json:"message"`
+	// A flat object schema whose properties are all primitive, describing the requested input
+	// 描述请求输入的扁平对象模式，其属性均为基本类型
+	RequestedSchema jsonschema.Definition `json:"requestedSchema"`
+}
+
+// ElicitResult is the client's response to an elicitation request
+// ElicitResult 是客户端对信息征询请求的响应
+type ElicitResult struct {
+	// The answer of the user
+	// 用户的回答
+	Action ElicitAction `json:"action"`
+	// The submitted input matching the requested schema, only present when the action is accept
+	// 符合请求模式的提交输入，仅在回答为 accept 时存在
+	Content json.RawMessage `json:"content,omitempty"`
+	// Reserved by MCP for additional metadata
+	// 保留给MCP用于附加元数据
+	Meta json.RawMessage `json:"_meta,omitempty"`
+}
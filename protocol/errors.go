@@ -0,0 +1,199 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Error is a JSON-RPC error with a code, a message and optional structured data
+// Error 是带有错误代码、消息和可选结构化数据的 JSON-RPC 错误
+//
+// Handlers may return it, possibly wrapped, and the router maps it to the error response through errors.As
+// 处理程序可以直接或包装后返回它，路由器通过 errors.As 将其映射为错误响应
+type Error struct {
+	// Error code (错误代码)
+	Code int64
+	// Short description of the error (错误的简短描述)
+	Message string
+	// Additional information about the error (关于错误的附加信息)
+	Data json.RawMessage
+
+	cause error
+}
+
+// NewError creates an error with the given code, the message is formatted like fmt.Errorf and may wrap a cause with %w
+// NewError 创建指定代码的错误，消息按 fmt.Errorf 格式化，可以使用 %w 包装原因
+func NewError(code int64, format string, args ...interface{}) *Error {
+	cause := fmt.Errorf(format, args...)
+	return &Error{
+		Code:    code,
+		Message: cause.Error(),
+		cause:   cause,
+	}
+}
+
+// NewErrorFromJsonrpc converts a JSON-RPC error response to an Error
+// NewErrorFromJsonrpc 将 JSON-RPC 错误响应转换为 Error
+func NewErrorFromJsonrpc(errInfo *JsonrpcError) *Error {
+	return &Error{
+		Code:    errInfo.Code,
+		Message: errInfo.Message,
+		Data:    errInfo.Data,
+	}
+}
+
+// Error implements the error interface
+// Error 实现 error 接口
+func (x *Error) Error() string {
+	return x.Message
+}
+
+// Unwrap returns the formatted cause of the error, which wraps the errors given with %w, nil if none
+// Unwrap 返回错误的格式化原因，其包装了通过 %w 传入的错误，没有则为 nil
+func (x *Error) Unwrap() error {
+	return x.cause
+}
+
+// WithData returns a copy of the error carrying data marshalled as JSON
+// WithData 返回携带 JSON 序列化数据的错误副本
+func (x *Error) WithData(data interface{}) *Error {
+	e := *x
+	bs, err := json.Marshal(data)
+	if err != nil {
+		bs, _ = json.Marshal(err.Error())
+	}
+	e.Data = bs
+	return &e
+}
+
+// DecodeData decodes the structured data of the error into v
+// DecodeData 将错误的结构化数据解码到 v
+func (x *Error) DecodeData(v interface{}) error {
+	if len(x.Data) == 0 {
+		return errors.New("error has no data")
+	}
+	return json.Unmarshal(x.Data, v)
+}
+
+// JsonrpcError converts the error to its JSON-RPC representation
+// JsonrpcError 将错误转换为 JSON-RPC 表示
+func (x *Error) JsonrpcError() *JsonrpcError {
+	return &JsonrpcError{
+		Code:    x.Code,
+		Message: x.Message,
+		Data:    x.Data,
+	}
+}
+
+// ErrorCode returns the code of the Error found in err's chain, ok is false if there is none
+// ErrorCode 返回 err 链中 Error 的代码，不存在时 ok 为 false
+func ErrorCode(err error) (code int64, ok bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, true
+	}
+	return 0, false
+}
+
+// NewParseError creates an error for invalid JSON received
+// NewParseError 创建接收到无效 JSON 的错误
+func NewParseError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeParseError, format, args...)
+}
+
+// NewInvalidRequestError creates an error for a message that is not a valid request
+// NewInvalidRequestError 创建消息不是有效请求的错误
+func NewInvalidRequestError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeInvalidRequest, format, args...)
+}
+
+// NewMethodNotFoundError creates an error for a method that does not exist or is not available
+// NewMethodNotFoundError 创建方法不存在或不可用的错误
+func NewMethodNotFoundError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeMethodNotFound, format, args...)
+}
+
+// NewInvalidParamsError creates an error for invalid method parameters
+// NewInvalidParamsError 创建方法参数无效的错误
+func NewInvalidParamsError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeInvalidParams, format, args...)
+}
+
+// NewInternalError creates an error for an internal failure
+// NewInternalError 创建内部错误
+func NewInternalError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeInternalError, format, args...)
+}
+
+// NewRequestCancelledError creates an error for a cancelled request
+// NewRequestCancelledError 创建请求已取消的错误
+func NewRequestCancelledError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeRequestCancelled, format, args...)
+}
+
+// NewContentModifiedError creates an error for content modified during the request
+// NewContentModifiedError 创建请求期间内容被修改的错误
+func NewContentModifiedError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeContentModified, format, args...)
+}
+
+// NewRequestFailedError creates an error for a request that failed
+// NewRequestFailedError 创建请求失败的错误
+func NewRequestFailedError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeRequestFailed, format, args...)
+}
+
+// NewServerNotInitializedError creates an error for a request received before initialization
+// NewServerNotInitializedError 创建在初始化之前收到请求的错误
+func NewServerNotInitializedError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeServerNotInitialized, format, args...)
+}
+
+// NewResourceNotFoundError creates an error for an unknown resource, the uri is attached as data
+// NewResourceNotFoundError 创建资源不存在的错误，uri 作为数据附加
+func NewResourceNotFoundError(uri string) *Error {
+	return NewError(ErrorCodeResourceNotFound, "resource %s not found", uri).WithData(map[string]string{"uri": uri})
+}
+
+// NewToolNotFoundError creates an error for an unknown tool, the name is attached as data
+// NewToolNotFoundError 创建工具不存在的错误，名称作为数据附加
+func NewToolNotFoundError(name string) *Error {
+	return NewError(ErrorCodeToolNotFound, "tool %s not found", name).WithData(map[string]string{"name": name})
+}
+
+// NewPromptNotFoundError creates an error for an unknown prompt, the name is attached as data
+// NewPromptNotFoundError 创建提示不存在的错误，名称作为数据附加
+func NewPromptNotFoundError(name string) *Error {
+	return NewError(ErrorCodePromptNotFound, "prompt %s not found", name).WithData(map[string]string{"name": name})
+}
+
+// NewResourceReadError creates an error for a resource that could not be read
+// NewResourceReadError 创建资源读取失败的错误
+func NewResourceReadError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeResourceReadError, format, args...)
+}
+
+// NewToolExecutionError creates an error for a tool that failed to execute
+// NewToolExecutionError 创建工具执行失败的错误
+func NewToolExecutionError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeToolExecutionError, format, args...)
+}
+
+// NewPromptExecutionError creates an error for a prompt that failed to execute
+// NewPromptExecutionError 创建提示执行失败的错误
+func NewPromptExecutionError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodePromptExecutionError, format, args...)
+}
+
+// NewResourceSubscribeError creates an error for a failed resource subscription
+// NewResourceSubscribeError 创建资源订阅失败的错误
+func NewResourceSubscribeError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeResourceSubscribeError, format, args...)
+}
+
+// NewResourceUnsubscribeError creates an error for a failed resource unsubscription
+// NewResourceUnsubscribeError 创建取消资源订阅失败的错误
+func NewResourceUnsubscribeError(format string, args ...interface{}) *Error {
+	return NewError(ErrorCodeResourceUnsubscribeError, format, args...)
+}
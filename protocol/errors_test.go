@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	err := NewInvalidParamsError("decode failed: %w", io.ErrUnexpectedEOF)
+	if err.Code != ErrorCodeInvalidParams || err.Error() != "decode failed: unexpected EOF" {
+		t.Errorf("unexpected error: %d %s", err.Code, err.Error())
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the cause to be unwrapped")
+	}
+	joined := NewInternalError("%w and %w", io.EOF, io.ErrClosedPipe)
+	if !errors.Is(joined, io.EOF) || !errors.Is(joined, io.ErrClosedPipe) {
+		t.Errorf("expected every cause to be unwrapped")
+	}
+
+	wrapped := fmt.Errorf("handler: %w", NewToolNotFoundError("echo"))
+	code, ok := ErrorCode(wrapped)
+	if !ok || code != ErrorCodeToolNotFound {
+		t.Errorf("unexpected code: %d %v", code, ok)
+	}
+	var rpcErr *Error
+	if !errors.As(wrapped, &rpcErr) {
+		t.Fatal("expected errors.As to find the error")
+	}
+	var data map[string]string
+	if err := rpcErr.DecodeData(&data); err != nil || data["name"] != "echo" {
+		t.Errorf("unexpected data: %v %v", data, err)
+	}
+
+	if _, ok := ErrorCode(io.EOF); ok {
+		t.Errorf("expected no code for a plain error")
+	}
+
+	converted := NewErrorFromJsonrpc(rpcErr.JsonrpcError())
+	if converted.Code != rpcErr.Code || converted.Message != rpcErr.Message || string(converted.Data) != string(rpcErr.Data) {
+		t.Errorf("unexpected round trip: %+v", converted)
+	}
+}
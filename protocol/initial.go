@@ -61,10 +61,22 @@ type ClientSampling struct {
 	// 规范不要求属性，它是一个能力标记
 }
 
+// ClientElicitation defines elicitation capabilities of a client
+// ClientElicitation 定义了客户端的信息征询能力
+// Servers can request structured input from the user through the client while handling a request,
+// the client decides how the user is asked and answers with accept, decline or cancel.
+type ClientElicitation struct {
+	// No properties required by the specification, it's a capability marker
+	// 规范不要求属性，它是一个能力标记
+}
+
 // Implementation describes the name and version of an MCP implementation
 // Implementation 描述了MCP实现的名称和版本
 type Implementation struct {
-	Name    string `json:"name"`
+	Name string `json:"name"`
+	// A human-readable title for the implementation, intended for UI display
+	// 实现的可读标题，用于界面展示
+	Title   string `json:"title,omitempty"`
 	Version string `json:"version"`
 }
 
@@ -78,6 +90,9 @@ type ClientCapabilities struct {
 	// Used to interact with large language models through the client
 	// 用于通过客户端与大型语言模型交互
 	Sampling *ClientSampling `json:"sampling,omitempty"`
+	// Used to request structured input from the user through the client
+	// 用于通过客户端向用户请求结构化输入
+	Elicitation *ClientElicitation `json:"elicitation,omitempty"`
 	// Experimental capabilities that the client supports
 	// 客户端支持的实验性功能
 	Experimental json.RawMessage `json:"experimental,omitempty"`
@@ -90,6 +105,13 @@ type ServerLogging struct {
 	// 规范不要求属性，它是一个能力标记
 }
 
+// ServerCompletions defines argument completion capabilities of a server
+// ServerCompletions 定义了服务器的参数补全能力
+type ServerCompletions struct {
+	// No properties required by the specification, it's a capability marker
+	// 规范不要求属性，它是一个能力标记
+}
+
 // ServerPrompts defines prompt capabilities of a server
 // ServerPrompts 定义了服务器的提示能力
 type ServerPrompts struct {
@@ -149,6 +171,9 @@ type ServerCapabilities struct {
 	// Tool invocation capabilities
 	// 工具调用能力
 	Tools *ServerTools `json:"tools,omitempty"`
+	// Argument completion capabilities
+	// 参数补全能力
+	Completions *ServerCompletions `json:"completions,omitempty"`
 	// Experimental capabilities that the server supports
 	// 服务器支持的实验性功能
 	Experimental json.RawMessage `json:"experimental,omitempty"`
@@ -29,6 +29,18 @@ func (x *JsonrpcRequest) GetID() json.RawMessage {
 	return x.ID
 }
 
+// Validate checks that the request is a well-formed JSON-RPC 2.0 request, the returned error is an invalid request error
+// Validate 检查请求是否为格式正确的 JSON-RPC 2.0 请求，返回的错误为无效请求错误
+func (x *JsonrpcRequest) Validate() error {
+	if x.Jsonrpc != JSONRPCVersion {
+		return NewInvalidRequestError("invalid jsonrpc version %q", x.Jsonrpc)
+	}
+	if x.Method == "" {
+		return NewInvalidRequestError("missing method")
+	}
+	return nil
+}
+
 // JsonrpcResponse represents a successful (non-error) response to a request
 // JsonrpcResponse 表示对请求的成功（非错误）响应
 type JsonrpcResponse JsonrpcPack
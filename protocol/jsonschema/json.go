@@ -146,3 +146,24 @@ func reflectSchemaObject(t reflect.Type) (*Definition, error) {
 	d.Properties = properties
 	return &d, nil
 }
+
+// GenerateFlatSchemaForType generates the restricted schema of an object whose properties are all primitive
+// (string, number, integer or boolean), as required to request input from a user
+func GenerateFlatSchemaForType(v any) (*Definition, error) {
+	d, err := GenerateSchemaForType(v)
+	if err != nil {
+		return nil, err
+	}
+	if d.Type != Object {
+		return nil, fmt.Errorf("flat schema requires an object, got %s", d.Type)
+	}
+	for name, property := range d.Properties {
+		switch property.Type {
+		case String, Number, Integer, Boolean:
+		default:
+			return nil, fmt.Errorf("flat schema property %s must be primitive, got %s", name, property.Type)
+		}
+	}
+	d.AdditionalProperties = nil
+	return d, nil
+}
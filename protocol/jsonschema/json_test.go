@@ -202,3 +202,34 @@ func structToMap(t *testing.T, v any) map[string]any {
 	}
 	return got
 }
+
+func TestGenerateFlatSchemaForType(t *testing.T) {
+	type confirm struct {
+		Confirm bool   `json:"confirm"`
+		Reason  string `json:"reason,omitempty" description:"why"`
+		Count   int    `json:"count"`
+	}
+	d, err := jsonschema.GenerateFlatSchemaForType(confirm{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Type != jsonschema.Object || d.AdditionalProperties != nil {
+		t.Errorf("unexpected schema: %+v", d)
+	}
+	if d.Properties["confirm"].Type != jsonschema.Boolean || d.Properties["reason"].Description != "why" {
+		t.Errorf("unexpected properties: %+v", d.Properties)
+	}
+	if !reflect.DeepEqual(d.Required, []string{"confirm", "count"}) {
+		t.Errorf("unexpected required: %v", d.Required)
+	}
+
+	type nested struct {
+		Tags []string `json:"tags"`
+	}
+	if _, err := jsonschema.GenerateFlatSchemaForType(nested{}); err == nil {
+		t.Error("expected an error for a non primitive property")
+	}
+	if _, err := jsonschema.GenerateFlatSchemaForType(""); err == nil {
+		t.Error("expected an error for a non object type")
+	}
+}
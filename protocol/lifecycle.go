@@ -2,24 +2,31 @@ package protocol
 
 import "encoding/json"
 
-// CancelRequest is sent to cancel a pending request
-type CancelRequest struct {
-	// ID of the request to cancel
-	ID json.RawMessage `json:"id"`
+// CancelledNotification is sent by either side to indicate that it is cancelling a previously-issued request
+type CancelledNotification struct {
+	// ID of the request to cancel, it must correspond to the ID of a request previously issued in the same direction
+	RequestID json.RawMessage `json:"requestId"`
+	// An optional string describing the reason for the cancellation
+	Reason string `json:"reason,omitempty"`
 }
 
-// CancelResult confirms the cancellation
-type CancelResult struct{}
+// ProgressToken identifies a specific ongoing operation, it can be a string or an integer
+type ProgressToken = json.RawMessage
 
-// ProgressToken identifies a specific ongoing operation
-type ProgressToken string
+// RequestMeta holds the well-known fields of the _meta of a request
+type RequestMeta struct {
+	// If specified, the caller is requesting out-of-band progress notifications for this request
+	ProgressToken ProgressToken `json:"progressToken,omitempty"`
+}
 
 // ProgressNotification reports progress for long-running operations
 type ProgressNotification struct {
-	// Token identifies the operation
-	Token ProgressToken `json:"token"`
-	// Value indicates the progress (0-100%)
-	Value int `json:"value"`
+	// ProgressToken is the token given in the _meta of the request that is in progress
+	ProgressToken ProgressToken `json:"progressToken"`
+	// Progress thus far, it must increase every time progress is notified
+	Progress float64 `json:"progress"`
+	// Total number of items to process, if known
+	Total float64 `json:"total,omitempty"`
 	// Message provides additional progress information
 	Message string `json:"message,omitempty"`
 }
@@ -8,6 +8,9 @@ type PromptArgument struct {
 	// The name of the argument
 	// 参数的名称
 	Name string `json:"name"`
+	// A human-readable title for the argument, intended for UI display
+	// 参数的可读标题，用于界面展示
+	Title string `json:"title,omitempty"`
 	// A human-readable description of the argument
 	// 参数的可读描述
 	Description string `json:"description,omitempty"`
@@ -22,6 +25,9 @@ type Prompt struct {
 	// The name of the prompt or prompt template
 	// 提示或提示模板的名称
 	Name string `json:"name"`
+	// A human-readable title for the prompt, intended for UI display
+	// 提示的可读标题，用于界面展示
+	Title string `json:"title,omitempty"`
 	// An optional description of what this prompt provides
 	// 这个提示提供什么的可选描述
 	Description string `json:"description,omitempty"`
@@ -14,6 +14,9 @@ type Resource struct {
 	// A human-readable name for this resource
 	// 这个资源的可读名称
 	Name string `json:"name"`
+	// A human-readable title for this resource, intended for UI display
+	// 这个资源的可读标题，用于界面展示
+	Title string `json:"title,omitempty"`
 	// A description of what this resource represents
 	// 这个资源表示什么的描述
 	Description string `json:"description,omitempty"`
@@ -37,6 +40,9 @@ type ResourceTemplate struct {
 	// A human-readable name for the type of resource this template refers to
 	// 此模板指向的资源类型的可读名称
 	Name string `json:"name"`
+	// A human-readable title for this template, intended for UI display
+	// 这个模板的可读标题，用于界面展示
+	Title string `json:"title,omitempty"`
 	// A description of what this template is for
 	// 这个模板的用途描述
 	Description string `json:"description,omitempty"`
@@ -12,12 +12,80 @@ type Tool struct {
 	// The name of the tool
 	// 工具的名称
 	Name string `json:"name"`
+	// A human-readable title for the tool, intended for UI display
+	// 工具的可读标题，用于界面展示
+	Title string `json:"title,omitempty"`
 	// A human-readable description of the tool
 	// 可读的工具描述
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool
 	// 定义工具参数的 JSON Schema 对象
 	InputSchema *jsonschema.Definition `json:"inputSchema,omitempty"`
+	// An optional JSON Schema object defining the structure of the tool's structured output
+	// 定义工具结构化输出的可选 JSON Schema 对象
+	OutputSchema *jsonschema.Definition `json:"outputSchema,omitempty"`
+	// Optional additional tool information
+	// 可选的附加工具信息
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describes the behaviour of a tool to clients
+// All properties are hints, clients should never make trust decisions based on annotations from untrusted servers
+// ToolAnnotations 向客户端描述工具的行为
+// 所有属性都只是提示，客户端不应基于不受信任服务器的注释做出信任决策
+type ToolAnnotations struct {
+	// A human-readable title for the tool
+	// 工具的可读标题
+	Title string `json:"title,omitempty"`
+	// If true, the tool does not modify its environment (default: false)
+	// 如果为 true，工具不会修改其环境（默认：false）
+	ReadOnlyHint *bool `json:"readOnlyHint,omitempty"`
+	// If true, the tool may perform destructive updates, only meaningful when readOnlyHint is false (default: true)
+	// 如果为 true，工具可能执行破坏性更新，仅当 readOnlyHint 为 false 时有意义（默认：true）
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	// If true, calling the tool repeatedly with the same arguments has no additional effect (default: false)
+	// 如果为 true，使用相同参数重复调用工具不会产生额外影响（默认：false）
+	IdempotentHint *bool `json:"idempotentHint,omitempty"`
+	// If true, the tool may interact with an "open world" of external entities (default: true)
+	// 如果为 true，工具可能与外部实体的“开放世界”交互（默认：true）
+	OpenWorldHint *bool `json:"openWorldHint,omitempty"`
+}
+
+// IsReadOnly reports the readOnlyHint, falling back to the default defined by the specification
+// IsReadOnly 返回 readOnlyHint，未设置时使用规范定义的默认值
+func (x *ToolAnnotations) IsReadOnly() bool {
+	return boolHint(x, func(a *ToolAnnotations) *bool { return a.ReadOnlyHint }, false)
+}
+
+// IsDestructive reports the destructiveHint, falling back to the default defined by the specification
+// IsDestructive 返回 destructiveHint，未设置时使用规范定义的默认值
+func (x *ToolAnnotations) IsDestructive() bool {
+	if x.IsReadOnly() {
+		return false
+	}
+	return boolHint(x, func(a *ToolAnnotations) *bool { return a.DestructiveHint }, true)
+}
+
+// IsIdempotent reports the idempotentHint, falling back to the default defined by the specification
+// IsIdempotent 返回 idempotentHint，未设置时使用规范定义的默认值
+func (x *ToolAnnotations) IsIdempotent() bool {
+	return boolHint(x, func(a *ToolAnnotations) *bool { return a.IdempotentHint }, false)
+}
+
+// IsOpenWorld reports the openWorldHint, falling back to the default defined by the specification
+// IsOpenWorld 返回 openWorldHint，未设置时使用规范定义的默认值
+func (x *ToolAnnotations) IsOpenWorld() bool {
+	return boolHint(x, func(a *ToolAnnotations) *bool { return a.OpenWorldHint }, true)
+}
+
+func boolHint(x *ToolAnnotations, field func(*ToolAnnotations) *bool, defaultValue bool) bool {
+	if x == nil {
+		return defaultValue
+	}
+	if v := field(x); v != nil {
+		return *v
+	}
+	return defaultValue
 }
 
 // ListToolsRequest is sent from client to request a list of tools the server has
@@ -55,8 +123,17 @@ type CallToolRequest struct {
 
 // We'll replace all the content structs with the ones from sampling since they're the same
 
+// Content types
+// 内容类型
+const (
+	ContentTypeText         = "text"
+	ContentTypeImage        = "image"
+	ContentTypeResource     = "resource"
+	ContentTypeResourceLink = "resource_link"
+)
+
 type Content struct {
-	// The type of content (text | image | resource)
+	// The type of content (text | image | resource | resource_link)
 	// 内容类型
 	Type string `json:"type"`
 	// Optional annotations for the content
@@ -77,11 +154,27 @@ type Content struct {
 	// The URI of the resource
 	// 资源的URI
 	Resource ResourceContent `json:"resource"`
+
+	// The URI of the linked resource (only for resource_link)
+	// 链接资源的URI（仅用于 resource_link）
+	URI string `json:"uri,omitempty"`
+	// A human-readable name for the linked resource (only for resource_link)
+	// 链接资源的可读名称（仅用于 resource_link）
+	Name string `json:"name,omitempty"`
+	// A human-readable title for the linked resource (only for resource_link)
+	// 链接资源的可读标题（仅用于 resource_link）
+	Title string `json:"title,omitempty"`
+	// A description of the linked resource (only for resource_link)
+	// 链接资源的描述（仅用于 resource_link）
+	Description string `json:"description,omitempty"`
+	// The size of the linked resource in bytes, if known (only for resource_link)
+	// 链接资源的大小（字节），如果已知（仅用于 resource_link）
+	Size int64 `json:"size,omitempty"`
 }
 
 func NewTextContent(text string, annotations *Annotations) Content {
 	return Content{
-		Type:        "text",
+		Type:        ContentTypeText,
 		Annotations: annotations,
 		Text:        text,
 	}
@@ -89,7 +182,7 @@ func NewTextContent(text string, annotations *Annotations) Content {
 
 func NewImageContent(data string, mimeType string, annotations *Annotations) Content {
 	return Content{
-		Type:        "image",
+		Type:        ContentTypeImage,
 		Annotations: annotations,
 		Data:        data,
 		MimeType:    mimeType,
@@ -98,11 +191,53 @@ func NewImageContent(data string, mimeType string, annotations *Annotations) Con
 
 func NewResourceContent(resource ResourceContent) Content {
 	return Content{
-		Type:     "resource",
+		Type:     ContentTypeResource,
 		Resource: resource,
 	}
 }
 
+// NewResourceLinkContent creates a content item that links to the given resource instead of embedding it
+// NewResourceLinkContent 创建一个链接到指定资源而不是嵌入资源的内容项
+func NewResourceLinkContent(resource Resource) Content {
+	return Content{
+		Type:        ContentTypeResourceLink,
+		Annotations: resource.Annotations,
+		MimeType:    resource.MimeType,
+		URI:         resource.URI,
+		Name:        resource.Name,
+		Title:       resource.Title,
+		Description: resource.Description,
+		Size:        resource.Size,
+	}
+}
+
+// NewResourceLinkContents creates a resource link content item for each of the given resources
+// NewResourceLinkContents 为每个给定的资源创建资源链接内容项
+func NewResourceLinkContents(resources ...Resource) []Content {
+	contents := make([]Content, len(resources))
+	for i, resource := range resources {
+		contents[i] = NewResourceLinkContent(resource)
+	}
+	return contents
+}
+
+// ResourceLink returns the resource referenced by a resource_link content item
+// ResourceLink 返回 resource_link 内容项引用的资源
+func (x Content) ResourceLink() (Resource, bool) {
+	if x.Type != ContentTypeResourceLink {
+		return Resource{}, false
+	}
+	return Resource{
+		URI:         x.URI,
+		Name:        x.Name,
+		Title:       x.Title,
+		Description: x.Description,
+		MimeType:    x.MimeType,
+		Size:        x.Size,
+		Annotations: x.Annotations,
+	}, true
+}
+
 // CallToolResult is the server's response to a tool call
 // CallToolResult 是服务器对工具调用的响应
 type CallToolResult struct {
@@ -112,6 +247,9 @@ type CallToolResult struct {
 	// Content items returned by the tool (can include text, images, or embedded resources)
 	// 工具返回的内容项（可以包括文本、图像或嵌入的资源）
 	Content []Content `json:"content"`
+	// An optional JSON object that represents the structured result of the tool call, matching the tool's outputSchema
+	// 表示工具调用结构化结果的可选 JSON 对象，与工具的 outputSchema 匹配
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
 	// Reserved by MCP for additional metadata
 	// 保留给MCP用于附加元数据
 	Meta json.RawMessage `json:"_meta,omitempty"`
@@ -37,3 +37,61 @@ func TestMarshalTools(t *testing.T) {
 		t.Errorf("unexpected json: %s", string(bs))
 	}
 }
+
+func TestResourceLinkContent(t *testing.T) {
+	resource := Resource{
+		URI:         "file:///tmp/report.csv",
+		Name:        "report.csv",
+		Description: "generated report",
+		MimeType:    "text/csv",
+		Size:        1024,
+	}
+	content := NewResourceLinkContent(resource)
+	if content.Type != ContentTypeResourceLink {
+		t.Fatalf("unexpected type: %s", content.Type)
+	}
+
+	bs, err := json.Marshal(content)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded Content
+	if err := json.Unmarshal(bs, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	link, ok := decoded.ResourceLink()
+	if !ok {
+		t.Fatalf("expected resource link, got: %s", string(bs))
+	}
+	if link != resource {
+		t.Errorf("unexpected link: %+v", link)
+	}
+
+	if _, ok := NewTextContent("foo", nil).ResourceLink(); ok {
+		t.Error("text content must not be a resource link")
+	}
+}
+
+func TestToolAnnotations(t *testing.T) {
+	var nilAnnotations *ToolAnnotations
+	if nilAnnotations.IsReadOnly() || !nilAnnotations.IsDestructive() || nilAnnotations.IsIdempotent() || !nilAnnotations.IsOpenWorld() {
+		t.Error("nil annotations must fall back to the defaults")
+	}
+
+	readOnly := true
+	bs, _ := json.Marshal(Tool{
+		Name:        "search",
+		Annotations: &ToolAnnotations{Title: "Search", ReadOnlyHint: &readOnly},
+	})
+	if string(bs) != `{"name":"search","annotations":{"title":"Search","readOnlyHint":true}}` {
+		t.Errorf("unexpected json: %s", string(bs))
+	}
+
+	var tool Tool
+	if err := json.Unmarshal(bs, &tool); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !tool.Annotations.IsReadOnly() || tool.Annotations.IsDestructive() {
+		t.Errorf("unexpected annotations: %+v", tool.Annotations)
+	}
+}
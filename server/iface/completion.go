@@ -0,0 +1,106 @@
+package iface
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// CompleterFunc completes the value typed so far for a single argument, arguments holds the values already resolved for the other ones
+type CompleterFunc func(ctx context.Context, value string, arguments map[string]string) (protocol.Completion, error)
+
+// NewCompletion builds a completion from all the matching values, keeping at most protocol.CompletionMaxValues of them
+func NewCompletion(values []string) protocol.Completion {
+	completion := protocol.Completion{
+		Values: values,
+		Total:  len(values),
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	if len(values) > protocol.CompletionMaxValues {
+		completion.Values = values[:protocol.CompletionMaxValues]
+		completion.HasMore = true
+	}
+	return completion
+}
+
+// NewEnumCompleter completes with the allowed values that start with the typed value, ignoring case
+func NewEnumCompleter(values ...string) CompleterFunc {
+	return func(_ context.Context, value string, _ map[string]string) (protocol.Completion, error) {
+		prefix := strings.ToLower(value)
+		matches := make([]string, 0, len(values))
+		for _, v := range values {
+			if strings.HasPrefix(strings.ToLower(v), prefix) {
+				matches = append(matches, v)
+			}
+		}
+		return NewCompletion(matches), nil
+	}
+}
+
+// CompletionRouter dispatches completion requests to the completer registered for a prompt argument or a resource template variable
+// Arguments without completer are completed with no value
+type CompletionRouter struct {
+	prompts   map[string]map[string]CompleterFunc
+	resources map[string]map[string]CompleterFunc
+}
+
+func NewCompletionRouter() *CompletionRouter {
+	return &CompletionRouter{
+		prompts:   make(map[string]map[string]CompleterFunc),
+		resources: make(map[string]map[string]CompleterFunc),
+	}
+}
+
+// Prompt registers the completer of an argument of a prompt
+func (x *CompletionRouter) Prompt(name string, argument string, completer CompleterFunc) *CompletionRouter {
+	register(x.prompts, name, argument, completer)
+	return x
+}
+
+// PromptEnum completes an argument of a prompt with its allowed values
+func (x *CompletionRouter) PromptEnum(name string, argument string, values ...string) *CompletionRouter {
+	return x.Prompt(name, argument, NewEnumCompleter(values...))
+}
+
+// Resource registers the completer of a variable of a resource template
+func (x *CompletionRouter) Resource(uriTemplate string, variable string, completer CompleterFunc) *CompletionRouter {
+	register(x.resources, uriTemplate, variable, completer)
+	return x
+}
+
+// ResourceEnum completes a variable of a resource template with its allowed values
+func (x *CompletionRouter) ResourceEnum(uriTemplate string, variable string, values ...string) *CompletionRouter {
+	return x.Resource(uriTemplate, variable, NewEnumCompleter(values...))
+}
+
+func (x *CompletionRouter) Build() ICompletion {
+	return x
+}
+
+func (x *CompletionRouter) Complete(ctx context.Context, ref protocol.CompletionReference, argument protocol.CompletionArgument, arguments map[string]string) (protocol.Completion, error) {
+	var completers map[string]CompleterFunc
+	switch ref.Type {
+	case protocol.CompletionRefPrompt:
+		completers = x.prompts[ref.Name]
+	case protocol.CompletionRefResource:
+		completers = x.resources[ref.URI]
+	default:
+		return protocol.Completion{}, protocol.NewInvalidParamsError("unknown completion reference type %q", ref.Type)
+	}
+
+	completer, ok := completers[argument.Name]
+	if !ok {
+		return NewCompletion(nil), nil
+	}
+	return completer(ctx, argument.Value, arguments)
+}
+
+func register(m map[string]map[string]CompleterFunc, key string, argument string, completer CompleterFunc) {
+	if m[key] == nil {
+		m[key] = make(map[string]CompleterFunc)
+	}
+	m[key][argument] = completer
+}
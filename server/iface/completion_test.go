@@ -0,0 +1,78 @@
+package iface
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+func TestEnumCompleter(t *testing.T) {
+	complete := NewEnumCompleter("python", "Pascal", "go")
+
+	completion, err := complete(context.Background(), "p", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(completion.Values) != 2 || completion.Values[0] != "python" || completion.Values[1] != "Pascal" {
+		t.Errorf("unexpected values: %v", completion.Values)
+	}
+	if completion.Total != 2 || completion.HasMore {
+		t.Errorf("unexpected total: %+v", completion)
+	}
+
+	completion, _ = complete(context.Background(), "rust", nil)
+	if completion.Values == nil || len(completion.Values) != 0 {
+		t.Errorf("expected empty values, got %#v", completion.Values)
+	}
+}
+
+func TestNewCompletionTruncates(t *testing.T) {
+	values := make([]string, protocol.CompletionMaxValues+5)
+	for i := range values {
+		values[i] = fmt.Sprintf("v%d", i)
+	}
+	completion := NewCompletion(values)
+	if len(completion.Values) != protocol.CompletionMaxValues {
+		t.Errorf("expected %d values, got %d", protocol.CompletionMaxValues, len(completion.Values))
+	}
+	if completion.Total != len(values) || !completion.HasMore {
+		t.Errorf("unexpected completion: total=%d hasMore=%v", completion.Total, completion.HasMore)
+	}
+}
+
+func TestCompletionRouter(t *testing.T) {
+	completion := NewCompletionRouter().
+		PromptEnum("code_review", "language", "python", "go").
+		Resource("file:///{path}", "path", func(_ context.Context, value string, arguments map[string]string) (protocol.Completion, error) {
+			return NewCompletion([]string{arguments["root"] + value}), nil
+		}).
+		Build()
+	ctx := context.Background()
+
+	got, err := completion.Complete(ctx, protocol.NewPromptReference("code_review"),
+		protocol.CompletionArgument{Name: "language", Value: "g"}, nil)
+	if err != nil || len(got.Values) != 1 || got.Values[0] != "go" {
+		t.Errorf("prompt completion: %v, %v", got.Values, err)
+	}
+
+	got, err = completion.Complete(ctx, protocol.NewResourceReference("file:///{path}"),
+		protocol.CompletionArgument{Name: "path", Value: "src"}, map[string]string{"root": "/tmp/"})
+	if err != nil || len(got.Values) != 1 || got.Values[0] != "/tmp/src" {
+		t.Errorf("resource completion: %v, %v", got.Values, err)
+	}
+
+	// 未注册的参数返回空结果
+	got, err = completion.Complete(ctx, protocol.NewPromptReference("unknown"),
+		protocol.CompletionArgument{Name: "language", Value: "g"}, nil)
+	if err != nil || got.Values == nil || len(got.Values) != 0 {
+		t.Errorf("unknown prompt: %#v, %v", got.Values, err)
+	}
+
+	// 未知的引用类型返回参数错误
+	_, err = completion.Complete(ctx, protocol.CompletionReference{Type: "ref/unknown"}, protocol.CompletionArgument{}, nil)
+	if code, ok := protocol.ErrorCode(err); !ok || code != protocol.ErrorCodeInvalidParams {
+		t.Errorf("expected invalid params, got %v", err)
+	}
+}
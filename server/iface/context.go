@@ -0,0 +1,100 @@
+package iface
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoResultContext is returned when a result field is set outside of a request handled by the server
+var ErrNoResultContext = errors.New("no result context found, the context was not created by the server")
+
+type (
+	resultExtensionKey struct{}
+	requestMetaKey     struct{}
+)
+
+// NewRequestMetaContext returns a context carrying the _meta of the request being handled
+func NewRequestMetaContext(ctx context.Context, meta json.RawMessage) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the _meta of the request being handled, nil if the request has none
+func RequestMetaFromContext(ctx context.Context) json.RawMessage {
+	meta, _ := ctx.Value(requestMetaKey{}).(json.RawMessage)
+	return meta
+}
+
+// DecodeRequestMeta decodes the _meta of the request being handled into v, it is a no-op if the request has none
+func DecodeRequestMeta(ctx context.Context, v any) error {
+	meta := RequestMetaFromContext(ctx)
+	if len(meta) == 0 {
+		return nil
+	}
+	return json.Unmarshal(meta, v)
+}
+
+// ResultExtension collects the optional result fields set by implementations while a request is handled
+type ResultExtension struct {
+	mu                sync.Mutex
+	structuredContent json.RawMessage
+	meta              json.RawMessage
+}
+
+// NewResultContext returns a context that collects the optional result fields of the current request
+func NewResultContext(ctx context.Context) (context.Context, *ResultExtension) {
+	ext := &ResultExtension{}
+	return context.WithValue(ctx, resultExtensionKey{}, ext), ext
+}
+
+// StructuredContent returns the structured content set for the current tool call
+func (x *ResultExtension) StructuredContent() json.RawMessage {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.structuredContent
+}
+
+// Meta returns the _meta set for the result of the current request
+func (x *ResultExtension) Meta() json.RawMessage {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.meta
+}
+
+// SetResultMeta attaches _meta to the result of the current request
+func SetResultMeta(ctx context.Context, meta any) error {
+	ext, ok := ctx.Value(resultExtensionKey{}).(*ResultExtension)
+	if !ok {
+		return ErrNoResultContext
+	}
+
+	bs, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result meta: %w", err)
+	}
+
+	ext.mu.Lock()
+	defer ext.mu.Unlock()
+	ext.meta = bs
+	return nil
+}
+
+// SetStructuredContent attaches structured content to the result of the current tool call
+func SetStructuredContent(ctx context.Context, v any) error {
+	ext, ok := ctx.Value(resultExtensionKey{}).(*ResultExtension)
+	if !ok {
+		return ErrNoResultContext
+	}
+
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+
+	ext.mu.Lock()
+	defer ext.mu.Unlock()
+	ext.structuredContent = bs
+	return nil
+}
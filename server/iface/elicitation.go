@@ -0,0 +1,66 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+	"github.com/mcp4go/mcp4go/protocol/jsonschema"
+)
+
+// ErrElicitationNotSupported is returned when the client did not declare the elicitation capability
+var ErrElicitationNotSupported = errors.New("client does not support elicitation")
+
+// ElicitResult is the answer of the user to an elicitation request
+type ElicitResult[T any] struct {
+	Action protocol.ElicitAction
+	// Content holds the submitted input, it is only set when the action is accept
+	Content T
+}
+
+// Accepted reports whether the user submitted the requested input
+func (x ElicitResult[T]) Accepted() bool {
+	return x.Action == protocol.ElicitActionAccept
+}
+
+// Elicit asks the user for input described by T through the client and blocks until the user answers
+// or ctx is done. T must be a struct whose fields are all primitive, see jsonschema.GenerateFlatSchemaForType
+func Elicit[T any](ctx context.Context, message string) (ElicitResult[T], error) {
+	var result ElicitResult[T]
+
+	session, ok := ClientSessionFromContext(ctx)
+	if !ok {
+		return result, ErrNoClientSession
+	}
+	if session.ClientCapabilities().Elicitation == nil {
+		return result, ErrElicitationNotSupported
+	}
+
+	var zero T
+	schema, err := jsonschema.GenerateFlatSchemaForType(zero)
+	if err != nil {
+		return result, fmt.Errorf("invalid elicitation schema: %w", err)
+	}
+
+	var resp protocol.ElicitResult
+	err = session.Request(ctx, protocol.MethodElicit, protocol.ElicitRequest{
+		Message:         message,
+		RequestedSchema: *schema,
+	}, &resp)
+	if err != nil {
+		return result, err
+	}
+
+	result.Action = resp.Action
+	switch resp.Action {
+	case protocol.ElicitActionAccept:
+		if err := jsonschema.VerifySchemaAndUnmarshal(*schema, resp.Content, &result.Content); err != nil {
+			return result, fmt.Errorf("invalid elicitation content: %w", err)
+		}
+	case protocol.ElicitActionDecline, protocol.ElicitActionCancel:
+	default:
+		return result, fmt.Errorf("unknown elicitation action %q", resp.Action)
+	}
+	return result, nil
+}
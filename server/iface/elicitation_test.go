@@ -0,0 +1,98 @@
+package iface
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+type fakeClientSession struct {
+	caps    protocol.ClientCapabilities
+	respond func(method protocol.McpMethod, params interface{}) (interface{}, error)
+	roots   []protocol.Root
+}
+
+func (x *fakeClientSession) ClientInfo() protocol.Implementation {
+	return protocol.Implementation{Name: "fake"}
+}
+
+func (x *fakeClientSession) ClientCapabilities() protocol.ClientCapabilities {
+	return x.caps
+}
+
+func (x *fakeClientSession) Request(_ context.Context, method protocol.McpMethod, params interface{}, result interface{}) error {
+	resp, err := x.respond(method, params)
+	if err != nil {
+		return err
+	}
+	bs, _ := json.Marshal(resp)
+	return json.Unmarshal(bs, result)
+}
+
+func (x *fakeClientSession) ListRoots(_ context.Context) ([]protocol.Root, error) {
+	return x.roots, nil
+}
+
+type deployConfirm struct {
+	Confirm bool   `json:"confirm"`
+	Env     string `json:"env"`
+}
+
+func TestElicit(t *testing.T) {
+	var request protocol.ElicitRequest
+	session := &fakeClientSession{
+		caps: protocol.ClientCapabilities{Elicitation: &protocol.ClientElicitation{}},
+		respond: func(method protocol.McpMethod, params interface{}) (interface{}, error) {
+			if method != protocol.MethodElicit {
+				t.Errorf("unexpected method %s", method)
+			}
+			request = params.(protocol.ElicitRequest)
+			return protocol.ElicitResult{
+				Action:  protocol.ElicitActionAccept,
+				Content: json.RawMessage(`{"confirm":true,"env":"prod"}`),
+			}, nil
+		},
+	}
+	ctx := NewClientSessionContext(context.Background(), session)
+
+	result, err := Elicit[deployConfirm](ctx, "deploy?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Accepted() || !result.Content.Confirm || result.Content.Env != "prod" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if request.Message != "deploy?" || len(request.RequestedSchema.Properties) != 2 {
+		t.Errorf("unexpected request: %+v", request)
+	}
+
+	// 拒绝时不返回内容
+	session.respond = func(protocol.McpMethod, interface{}) (interface{}, error) {
+		return protocol.ElicitResult{Action: protocol.ElicitActionDecline}, nil
+	}
+	result, err = Elicit[deployConfirm](ctx, "deploy?")
+	if err != nil || result.Accepted() || result.Action != protocol.ElicitActionDecline {
+		t.Errorf("unexpected decline: %+v, %v", result, err)
+	}
+
+	// 内容不符合模式
+	session.respond = func(protocol.McpMethod, interface{}) (interface{}, error) {
+		return protocol.ElicitResult{Action: protocol.ElicitActionAccept, Content: json.RawMessage(`{"confirm":"yes"}`)}, nil
+	}
+	if _, err = Elicit[deployConfirm](ctx, "deploy?"); err == nil {
+		t.Error("expected an error for invalid content")
+	}
+
+	// 客户端未声明能力
+	session.caps = protocol.ClientCapabilities{}
+	if _, err = Elicit[deployConfirm](ctx, "deploy?"); !errors.Is(err, ErrElicitationNotSupported) {
+		t.Errorf("expected ErrElicitationNotSupported, got %v", err)
+	}
+
+	if _, err = Elicit[deployConfirm](context.Background(), "deploy?"); !errors.Is(err, ErrNoClientSession) {
+		t.Errorf("expected ErrNoClientSession, got %v", err)
+	}
+}
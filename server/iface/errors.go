@@ -0,0 +1,61 @@
+package iface
+
+import (
+	"strings"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// ToolError is a tool execution failure carrying the content reported to the model
+// Tools return it to describe the failure with rich content, any other non-protocol error is reported as its text
+type ToolError struct {
+	content []protocol.Content
+}
+
+// NewToolError creates a tool execution failure with the given content
+func NewToolError(content ...protocol.Content) *ToolError {
+	return &ToolError{content: content}
+}
+
+// NewToolErrorText creates a tool execution failure with a text content
+func NewToolErrorText(text string) *ToolError {
+	return NewToolError(protocol.NewTextContent(text, nil))
+}
+
+// Content returns the content reported to the model
+func (x *ToolError) Content() []protocol.Content {
+	return x.content
+}
+
+// Error joins the text contents of the failure
+func (x *ToolError) Error() string {
+	texts := make([]string, 0, len(x.content))
+	for _, content := range x.content {
+		if content.Type == protocol.ContentTypeText {
+			texts = append(texts, content.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return "tool execution failed"
+	}
+	return strings.Join(texts, "\n")
+}
+
+// ToolCallError rejects a tool call before the tool runs, such as an unknown tool or invalid arguments
+// It is reported to the client as the protocol error it wraps, unlike the errors returned by the tool itself
+type ToolCallError struct {
+	err *protocol.Error
+}
+
+// NewToolCallError creates the rejection of a tool call reported as err
+func NewToolCallError(err *protocol.Error) *ToolCallError {
+	return &ToolCallError{err: err}
+}
+
+func (x *ToolCallError) Error() string {
+	return x.err.Error()
+}
+
+func (x *ToolCallError) Unwrap() error {
+	return x.err
+}
@@ -0,0 +1,43 @@
+package iface
+
+import (
+	"context"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// IClientLogger sends log messages to the client of the session being handled
+// Messages below the level set by the client through logging/setLevel are dropped
+type IClientLogger interface {
+	// Log sends a notifications/message, data is any value marshalled as JSON
+	Log(ctx context.Context, level protocol.LoggingLevel, logger string, data interface{}) error
+	// Enabled reports whether messages of the given level are sent to the client
+	Enabled(level protocol.LoggingLevel) bool
+}
+
+type clientLoggerKey struct{}
+
+// NewClientLoggerContext returns a context carrying the client logger of the session being handled
+func NewClientLoggerContext(ctx context.Context, logger IClientLogger) context.Context {
+	return context.WithValue(ctx, clientLoggerKey{}, logger)
+}
+
+// ClientLoggerFromContext returns the client logger of the session being handled
+// Outside of a request handled by the server, the returned logger drops every message
+func ClientLoggerFromContext(ctx context.Context) IClientLogger {
+	logger, ok := ctx.Value(clientLoggerKey{}).(IClientLogger)
+	if !ok {
+		return nopClientLogger{}
+	}
+	return logger
+}
+
+type nopClientLogger struct{}
+
+func (nopClientLogger) Log(_ context.Context, _ protocol.LoggingLevel, _ string, _ interface{}) error {
+	return nil
+}
+
+func (nopClientLogger) Enabled(_ protocol.LoggingLevel) bool {
+	return false
+}
@@ -0,0 +1,41 @@
+package iface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+type recordClientLogger struct {
+	logged []protocol.LoggingLevel
+}
+
+func (x *recordClientLogger) Log(_ context.Context, level protocol.LoggingLevel, _ string, _ interface{}) error {
+	x.logged = append(x.logged, level)
+	return nil
+}
+
+func (x *recordClientLogger) Enabled(_ protocol.LoggingLevel) bool {
+	return true
+}
+
+func TestClientLoggerFromContext(t *testing.T) {
+	// 服务器之外的上下文返回丢弃消息的日志记录器
+	logger := ClientLoggerFromContext(context.Background())
+	if logger.Enabled(protocol.LoggingLevelEmergency) {
+		t.Error("expected the default logger to be disabled")
+	}
+	if err := logger.Log(context.Background(), protocol.LoggingLevelError, "test", "dropped"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	record := &recordClientLogger{}
+	ctx := NewClientLoggerContext(context.Background(), record)
+	if err := ClientLoggerFromContext(ctx).Log(ctx, protocol.LoggingLevelInfo, "test", "sent"); err != nil {
+		t.Fatal(err)
+	}
+	if len(record.logged) != 1 || record.logged[0] != protocol.LoggingLevelInfo {
+		t.Errorf("unexpected logged levels: %v", record.logged)
+	}
+}
@@ -0,0 +1,87 @@
+package iface
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// ErrProgressNotIncreasing is returned when a reported progress is not greater than the previous one
+var ErrProgressNotIncreasing = errors.New("progress must increase with each notification")
+
+// IProgressReporter reports the progress of the request being handled to the client
+type IProgressReporter interface {
+	// Report sends a progress notification, total is optional (0 if unknown) and progress must increase with each call
+	Report(ctx context.Context, progress float64, total float64, message string) error
+}
+
+type progressReporterKey struct{}
+
+// NewProgressReporterContext returns a context carrying the progress reporter of the request being handled
+func NewProgressReporterContext(ctx context.Context, reporter IProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+// ProgressReporterFromContext returns the progress reporter of the request being handled
+// When the client did not ask for progress, the returned reporter does nothing
+func ProgressReporterFromContext(ctx context.Context) IProgressReporter {
+	reporter, ok := ctx.Value(progressReporterKey{}).(IProgressReporter)
+	if !ok {
+		return nopProgressReporter{}
+	}
+	return reporter
+}
+
+// NewProgressReporter creates a reporter bound to the given progress token
+// It does nothing if the token is empty
+func NewProgressReporter(token protocol.ProgressToken, ch chan<- protocol.ProgressNotification) IProgressReporter {
+	// a null token is the same as no token
+	if trimmed := bytes.TrimSpace(token); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nopProgressReporter{}
+	}
+	return &progressReporter{
+		token: token,
+		ch:    ch,
+	}
+}
+
+type progressReporter struct {
+	mu       sync.Mutex
+	token    protocol.ProgressToken
+	ch       chan<- protocol.ProgressNotification
+	reported bool
+	last     float64
+}
+
+func (x *progressReporter) Report(ctx context.Context, progress float64, total float64, message string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.reported && progress <= x.last {
+		return ErrProgressNotIncreasing
+	}
+
+	select {
+	case x.ch <- protocol.ProgressNotification{
+		ProgressToken: x.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	x.reported = true
+	x.last = progress
+	return nil
+}
+
+type nopProgressReporter struct{}
+
+func (nopProgressReporter) Report(_ context.Context, _ float64, _ float64, _ string) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package iface
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+func TestProgressReporter(t *testing.T) {
+	ctx := context.Background()
+
+	// no progress token requested, reporting does nothing
+	if err := ProgressReporterFromContext(ctx).Report(ctx, 1, 0, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ch := make(chan protocol.ProgressNotification, 4)
+
+	// a null progress token is not a token
+	if err := NewProgressReporter(json.RawMessage(` null`), ch).Report(ctx, 1, 0, ""); err != nil || len(ch) != 0 {
+		t.Fatalf("unexpected report for null token: %v, %d notifications", err, len(ch))
+	}
+
+	ctx = NewProgressReporterContext(ctx, NewProgressReporter(json.RawMessage(`"abc"`), ch))
+	reporter := ProgressReporterFromContext(ctx)
+	if err := reporter.Report(ctx, 1, 10, "started"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := reporter.Report(ctx, 1, 10, "again"); err != ErrProgressNotIncreasing {
+		t.Fatalf("expected ErrProgressNotIncreasing, got %v", err)
+	}
+	if err := reporter.Report(ctx, 2, 10, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ch) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(ch))
+	}
+	bs, _ := json.Marshal(<-ch)
+	if string(bs) != `{"progressToken":"abc","progress":1,"total":10,"message":"started"}` {
+		t.Errorf("unexpected notification: %s", string(bs))
+	}
+}
@@ -52,8 +52,20 @@ type ITool interface {
 	// List returns available tools
 	List(ctx context.Context, cursor string) ([]protocol.Tool, string, error)
 
-	// Call invokes the specified tool operation
+	// Call invokes the specified tool operation, a call rejected before the tool runs returns a *ToolCallError
 	Call(ctx context.Context, name string, argsJSON json.RawMessage) ([]protocol.Content, error)
 
 	StartWatchListChanged(ctx context.Context, uri string, ch chan<- protocol.ToolListChangedNotification) error
 }
+
+type ICompletionBuilder interface {
+	Build() ICompletion
+}
+
+// ICompletion defines the argument completion interface
+type ICompletion interface {
+	// Complete returns the completion options for an argument of a prompt or a variable of a resource template,
+	// arguments holds the values already resolved for the other ones, use NewCompletion to keep at most
+	// protocol.CompletionMaxValues values
+	Complete(ctx context.Context, ref protocol.CompletionReference, argument protocol.CompletionArgument, arguments map[string]string) (protocol.Completion, error)
+}
@@ -0,0 +1,24 @@
+package iface
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// ErrRootsNotSupported is returned when the client did not declare the roots capability
+var ErrRootsNotSupported = errors.New("client does not support roots")
+
+// ListRoots returns the roots exposed by the client of the session being handled
+// The roots are cached per session until the client notifies they changed
+func ListRoots(ctx context.Context) ([]protocol.Root, error) {
+	session, ok := ClientSessionFromContext(ctx)
+	if !ok {
+		return nil, ErrNoClientSession
+	}
+	if session.ClientCapabilities().Roots == nil {
+		return nil, ErrRootsNotSupported
+	}
+	return session.ListRoots(ctx)
+}
@@ -0,0 +1,27 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+func TestListRoots(t *testing.T) {
+	session := &fakeClientSession{
+		caps:  protocol.ClientCapabilities{Roots: &protocol.ClientRoots{}},
+		roots: []protocol.Root{{URI: "file:///workspace"}},
+	}
+	ctx := NewClientSessionContext(context.Background(), session)
+
+	roots, err := ListRoots(ctx)
+	if err != nil || len(roots) != 1 || roots[0].URI != "file:///workspace" {
+		t.Errorf("unexpected roots: %+v, %v", roots, err)
+	}
+
+	session.caps = protocol.ClientCapabilities{}
+	if _, err = ListRoots(ctx); !errors.Is(err, ErrRootsNotSupported) {
+		t.Errorf("expected ErrRootsNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package iface
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// ErrSamplingNotSupported is returned when the client did not declare the sampling capability
+var ErrSamplingNotSupported = errors.New("client does not support sampling")
+
+// CreateMessage asks the client to sample its language model and blocks until the client answers, ctx is done
+// or the request timeout of the server expires
+func CreateMessage(ctx context.Context, request protocol.CreateMessageRequest) (protocol.CreateMessageResult, error) {
+	session, ok := ClientSessionFromContext(ctx)
+	if !ok {
+		return protocol.CreateMessageResult{}, ErrNoClientSession
+	}
+	if session.ClientCapabilities().Sampling == nil {
+		return protocol.CreateMessageResult{}, ErrSamplingNotSupported
+	}
+
+	var result protocol.CreateMessageResult
+	if err := session.Request(ctx, protocol.MethodCreateMessage, request, &result); err != nil {
+		return protocol.CreateMessageResult{}, err
+	}
+	return result, nil
+}
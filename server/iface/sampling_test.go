@@ -0,0 +1,40 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+func TestCreateMessage(t *testing.T) {
+	session := &fakeClientSession{
+		caps: protocol.ClientCapabilities{Sampling: &protocol.ClientSampling{}},
+		respond: func(method protocol.McpMethod, params interface{}) (interface{}, error) {
+			if method != protocol.MethodCreateMessage {
+				t.Errorf("unexpected method %s", method)
+			}
+			if params.(protocol.CreateMessageRequest).MaxTokens != 10 {
+				t.Errorf("unexpected params: %+v", params)
+			}
+			return protocol.CreateMessageResult{Model: "test-model"}, nil
+		},
+	}
+	ctx := NewClientSessionContext(context.Background(), session)
+
+	result, err := CreateMessage(ctx, protocol.CreateMessageRequest{MaxTokens: 10})
+	if err != nil || result.Model != "test-model" {
+		t.Errorf("unexpected result: %+v, %v", result, err)
+	}
+
+	// 客户端未声明能力
+	session.caps = protocol.ClientCapabilities{}
+	if _, err = CreateMessage(ctx, protocol.CreateMessageRequest{}); !errors.Is(err, ErrSamplingNotSupported) {
+		t.Errorf("expected ErrSamplingNotSupported, got %v", err)
+	}
+
+	if _, err = CreateMessage(context.Background(), protocol.CreateMessageRequest{}); !errors.Is(err, ErrNoClientSession) {
+		t.Errorf("expected ErrNoClientSession, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package iface
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// ErrNoClientSession is returned when the client is requested outside of a request handled by the server
+var ErrNoClientSession = errors.New("no client session found, the context was not created by the server")
+
+// IClientSession gives handlers access to the client connected to the session being handled
+type IClientSession interface {
+	// ClientInfo returns the implementation information sent by the client in initialize
+	ClientInfo() protocol.Implementation
+	// ClientCapabilities returns the capabilities declared by the client in initialize
+	ClientCapabilities() protocol.ClientCapabilities
+	// Request sends a request to the client and waits for its response, an error response is returned as *ClientRequestError
+	Request(ctx context.Context, method protocol.McpMethod, params interface{}, result interface{}) error
+	// ListRoots returns the roots of the client, they are cached until the client notifies they changed
+	ListRoots(ctx context.Context) ([]protocol.Root, error)
+}
+
+// ClientRequestError is returned when the client answers a request of the server with an error
+// It does not unwrap to the protocol.Error of the client, so that returning it from a handler is not
+// mistaken for a protocol error of the server
+type ClientRequestError struct {
+	// Method is the method of the request sent to the client
+	Method protocol.McpMethod
+	// Err is the error answered by the client
+	Err *protocol.Error
+}
+
+func NewClientRequestError(method protocol.McpMethod, err *protocol.Error) *ClientRequestError {
+	return &ClientRequestError{
+		Method: method,
+		Err:    err,
+	}
+}
+
+func (x *ClientRequestError) Error() string {
+	return fmt.Sprintf("client request %s failed: %s", x.Method, x.Err.Error())
+}
+
+type clientSessionKey struct{}
+
+// NewClientSessionContext returns a context carrying the client session of the request being handled
+func NewClientSessionContext(ctx context.Context, session IClientSession) context.Context {
+	return context.WithValue(ctx, clientSessionKey{}, session)
+}
+
+// ClientSessionFromContext returns the client session of the request being handled
+func ClientSessionFromContext(ctx context.Context) (IClientSession, bool) {
+	session, ok := ctx.Value(clientSessionKey{}).(IClientSession)
+	return session, ok
+}
+
+// ClientExperimentalCapability decodes the experimental capability name declared by the client into v,
+// ok is false if the client did not declare it
+func ClientExperimentalCapability(ctx context.Context, name string, v interface{}) (ok bool, err error) {
+	session, found := ClientSessionFromContext(ctx)
+	if !found {
+		return false, ErrNoClientSession
+	}
+	experimental := session.ClientCapabilities().Experimental
+	if len(experimental) == 0 {
+		return false, nil
+	}
+
+	var capabilities map[string]json.RawMessage
+	if err := json.Unmarshal(experimental, &capabilities); err != nil {
+		return false, fmt.Errorf("invalid experimental capabilities: %w", err)
+	}
+	capability, ok := capabilities[name]
+	if !ok {
+		return false, nil
+	}
+	if v != nil {
+		if err := json.Unmarshal(capability, v); err != nil {
+			return true, fmt.Errorf("invalid experimental capability %s: %w", name, err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,26 @@
+package iface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+func TestClientExperimentalCapability(t *testing.T) {
+	session := &fakeClientSession{
+		caps: protocol.ClientCapabilities{Experimental: []byte(`{"vendor/share":{"enabled":true}}`)},
+	}
+	ctx := NewClientSessionContext(context.Background(), session)
+
+	var share struct {
+		Enabled bool `json:"enabled"`
+	}
+	ok, err := ClientExperimentalCapability(ctx, "vendor/share", &share)
+	if err != nil || !ok || !share.Enabled {
+		t.Errorf("unexpected capability: %v, %+v, %v", ok, share, err)
+	}
+	if ok, err = ClientExperimentalCapability(ctx, "vendor/other", nil); ok || err != nil {
+		t.Errorf("expected undeclared capability, got %v, %v", ok, err)
+	}
+}
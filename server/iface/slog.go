@@ -0,0 +1,215 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// slog levels of the MCP logging levels that have no slog counterpart
+const (
+	SlogLevelNotice    = slog.Level(2)
+	SlogLevelCritical  = slog.Level(12)
+	SlogLevelAlert     = slog.Level(16)
+	SlogLevelEmergency = slog.Level(20)
+)
+
+// DefaultSlogLoggerKey is the attribute holding the logger name of a record
+const DefaultSlogLoggerKey = "logger"
+
+// SlogLevel maps a slog level onto the MCP logging level it falls into
+func SlogLevel(level slog.Level) protocol.LoggingLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return protocol.LoggingLevelDebug
+	case level < SlogLevelNotice:
+		return protocol.LoggingLevelInfo
+	case level < slog.LevelWarn:
+		return protocol.LoggingLevelNotice
+	case level < slog.LevelError:
+		return protocol.LoggingLevelWarning
+	case level < SlogLevelCritical:
+		return protocol.LoggingLevelError
+	case level < SlogLevelAlert:
+		return protocol.LoggingLevelCritical
+	case level < SlogLevelEmergency:
+		return protocol.LoggingLevelAlert
+	default:
+		return protocol.LoggingLevelEmergency
+	}
+}
+
+type SlogHandlerOptions struct {
+	loggerName string
+	loggerKey  string
+	tee        slog.Handler
+}
+
+type ISlogHandlerOption interface {
+	apply(*SlogHandlerOptions)
+}
+
+type SlogHandlerOptionFunc func(*SlogHandlerOptions)
+
+func (f SlogHandlerOptionFunc) apply(opts *SlogHandlerOptions) {
+	f(opts)
+}
+
+// WithSlogHandlerLoggerName sets the logger name of the records without logger attribute nor group
+func WithSlogHandlerLoggerName(name string) SlogHandlerOptionFunc {
+	return func(opts *SlogHandlerOptions) {
+		opts.loggerName = name
+	}
+}
+
+// WithSlogHandlerLoggerKey sets the attribute holding the logger name, DefaultSlogLoggerKey by default
+func WithSlogHandlerLoggerKey(key string) SlogHandlerOptionFunc {
+	return func(opts *SlogHandlerOptions) {
+		opts.loggerKey = key
+	}
+}
+
+// WithSlogHandlerTee also passes every record to a local handler, whatever the level set by the client
+func WithSlogHandlerTee(handler slog.Handler) SlogHandlerOptionFunc {
+	return func(opts *SlogHandlerOptions) {
+		opts.tee = handler
+	}
+}
+
+// SlogHandler is a slog.Handler sending records to the client of the session found in the context
+// given to the logger, see ClientLoggerFromContext. Records are sent as notifications/message whose data
+// is an object holding the message under "msg" and the attributes of the record
+//
+// The logger name comes from the logger attribute, or else from the groups joined with ".", which then
+// do not nest the attributes
+type SlogHandler struct {
+	options SlogHandlerOptions
+	attrs   []slog.Attr
+	groups  []string
+}
+
+func NewSlogHandler(opts ...ISlogHandlerOption) *SlogHandler {
+	options := SlogHandlerOptions{
+		loggerKey: DefaultSlogLoggerKey,
+	}
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	return &SlogHandler{
+		options: options,
+	}
+}
+
+func (x *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if ClientLoggerFromContext(ctx).Enabled(SlogLevel(level)) {
+		return true
+	}
+	return x.options.tee != nil && x.options.tee.Enabled(ctx, level)
+}
+
+func (x *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	var teeErr error
+	if x.options.tee != nil && x.options.tee.Enabled(ctx, record.Level) {
+		teeErr = x.options.tee.Handle(ctx, record)
+	}
+
+	level := SlogLevel(record.Level)
+	clientLogger := ClientLoggerFromContext(ctx)
+	if !clientLogger.Enabled(level) {
+		return teeErr
+	}
+
+	name := x.options.loggerName
+	if len(x.groups) > 0 {
+		name = strings.Join(x.groups, ".")
+	}
+	data := map[string]interface{}{
+		slog.MessageKey: record.Message,
+	}
+	addAttr := func(attr slog.Attr) bool {
+		if attr.Key == x.options.loggerKey {
+			name = attr.Value.Resolve().String()
+			return true
+		}
+		addSlogAttr(data, attr)
+		return true
+	}
+	for _, attr := range x.attrs {
+		addAttr(attr)
+	}
+	record.Attrs(addAttr)
+
+	return errors.Join(teeErr, clientLogger.Log(ctx, level, name, data))
+}
+
+func (x *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return x
+	}
+	h := x.clone()
+	h.attrs = append(h.attrs, attrs...)
+	if h.options.tee != nil {
+		h.options.tee = h.options.tee.WithAttrs(attrs)
+	}
+	return h
+}
+
+func (x *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return x
+	}
+	h := x.clone()
+	h.groups = append(h.groups, name)
+	if h.options.tee != nil {
+		h.options.tee = h.options.tee.WithGroup(name)
+	}
+	return h
+}
+
+func (x *SlogHandler) clone() *SlogHandler {
+	return &SlogHandler{
+		options: x.options,
+		attrs:   append([]slog.Attr(nil), x.attrs...),
+		groups:  append([]string(nil), x.groups...),
+	}
+}
+
+// addSlogAttr adds an attribute to the data of a record, group attributes become nested objects
+func addSlogAttr(data map[string]interface{}, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	switch value.Kind() {
+	case slog.KindGroup:
+		attrs := value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		// the attributes of a group without key are inlined
+		group := data
+		if attr.Key != "" {
+			group = make(map[string]interface{}, len(attrs))
+			data[attr.Key] = group
+		}
+		for _, a := range attrs {
+			addSlogAttr(group, a)
+		}
+	case slog.KindTime:
+		data[attr.Key] = value.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		data[attr.Key] = value.Duration().String()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			data[attr.Key] = err.Error()
+			return
+		}
+		data[attr.Key] = value.Any()
+	default:
+		data[attr.Key] = value.Any()
+	}
+}
@@ -0,0 +1,102 @@
+package iface
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+type thresholdClientLogger struct {
+	threshold slog.Level
+	messages  []protocol.LoggingMessageNotification
+}
+
+func (x *thresholdClientLogger) Log(_ context.Context, level protocol.LoggingLevel, logger string, data interface{}) error {
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	x.messages = append(x.messages, protocol.LoggingMessageNotification{Level: level, Logger: logger, Data: bs})
+	return nil
+}
+
+func (x *thresholdClientLogger) Enabled(level protocol.LoggingLevel) bool {
+	return level != protocol.LoggingLevelDebug || x.threshold < slog.LevelInfo
+}
+
+func TestSlogLevel(t *testing.T) {
+	cases := map[slog.Level]protocol.LoggingLevel{
+		slog.LevelDebug:    protocol.LoggingLevelDebug,
+		slog.LevelInfo:     protocol.LoggingLevelInfo,
+		SlogLevelNotice:    protocol.LoggingLevelNotice,
+		slog.LevelWarn:     protocol.LoggingLevelWarning,
+		slog.LevelError:    protocol.LoggingLevelError,
+		SlogLevelCritical:  protocol.LoggingLevelCritical,
+		SlogLevelAlert:     protocol.LoggingLevelAlert,
+		SlogLevelEmergency: protocol.LoggingLevelEmergency,
+		slog.LevelInfo + 1: protocol.LoggingLevelInfo,
+	}
+	for level, want := range cases {
+		if got := SlogLevel(level); got != want {
+			t.Errorf("SlogLevel(%v) = %s, want %s", level, got, want)
+		}
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	client := &thresholdClientLogger{threshold: slog.LevelInfo}
+	ctx := NewClientLoggerContext(context.Background(), client)
+	logger := slog.New(NewSlogHandler(WithSlogHandlerLoggerName("app")))
+
+	// 低于客户端设置级别的记录被丢弃
+	logger.DebugContext(ctx, "dropped")
+	logger.With("user", "bob").InfoContext(ctx, "login", slog.Group("req", "id", 7), "err", errors.New("boom"))
+	logger.WithGroup("db").WarnContext(ctx, "slow")
+	logger.ErrorContext(ctx, "named", DefaultSlogLoggerKey, "custom")
+	// 会话之外的上下文不发送
+	logger.ErrorContext(context.Background(), "no session")
+
+	if len(client.messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d: %+v", len(client.messages), client.messages)
+	}
+
+	first := client.messages[0]
+	if first.Level != protocol.LoggingLevelInfo || first.Logger != "app" {
+		t.Errorf("unexpected first message: %+v", first)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(first.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data["msg"] != "login" || data["user"] != "bob" || data["err"] != "boom" {
+		t.Errorf("unexpected data: %s", first.Data)
+	}
+	if req, ok := data["req"].(map[string]interface{}); !ok || req["id"] != float64(7) {
+		t.Errorf("expected nested group, got: %s", first.Data)
+	}
+
+	if second := client.messages[1]; second.Logger != "db" || second.Level != protocol.LoggingLevelWarning {
+		t.Errorf("expected group as logger name, got: %+v", second)
+	}
+	if third := client.messages[2]; third.Logger != "custom" || strings.Contains(string(third.Data), DefaultSlogLoggerKey) {
+		t.Errorf("expected logger attribute as logger name, got: %+v %s", third, third.Data)
+	}
+}
+
+func TestSlogHandlerTee(t *testing.T) {
+	var buf bytes.Buffer
+	local := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(NewSlogHandler(WithSlogHandlerTee(local))).With("k", "v")
+
+	// 没有会话时仍写入本地处理程序
+	logger.DebugContext(context.Background(), "local only")
+	if !strings.Contains(buf.String(), "local only") || !strings.Contains(buf.String(), "k=v") {
+		t.Errorf("expected the record to be teed, got: %s", buf.String())
+	}
+}
@@ -25,12 +25,32 @@ type IToolUnit interface {
 	Call(ctx context.Context, argsJSON json.RawMessage) ([]protocol.Content, error)
 }
 
+// IToolTitle is implemented by the tools having a human-readable title
+type IToolTitle interface {
+	// Title returns the human-readable title of the tool, empty if not set
+	Title() string
+}
+
+// IToolOutputSchema is implemented by the tools returning structured output
+type IToolOutputSchema interface {
+	// OutputSchema returns the JSON schema of the tool's structured output, nil if the tool has none
+	OutputSchema() *jsonschema.Definition
+}
+
+// IToolAnnotations is implemented by the tools giving behaviour hints
+type IToolAnnotations interface {
+	// Annotations returns the behaviour hints of the tool, nil if not set
+	Annotations() *protocol.ToolAnnotations
+}
+
 type ToolsCallbackFunc[T any] func(ctx context.Context, args T) ([]protocol.Content, error)
 
 type FunctionalToolDecodeFunc func(argsJSON json.RawMessage, receiver any) error
 
 type FunctionalToolWrapperOptions struct {
-	decodeFunc FunctionalToolDecodeFunc
+	decodeFunc  FunctionalToolDecodeFunc
+	title       string
+	annotations *protocol.ToolAnnotations
 }
 
 type IFunctionalToolWrapperOption interface {
@@ -49,6 +69,55 @@ func WithFunctionalToolWrapperDecodeFunc(decodeFunc FunctionalToolDecodeFunc) Fu
 	}
 }
 
+// WithFunctionalToolWrapperTitle sets the human-readable title of the tool
+func WithFunctionalToolWrapperTitle(title string) FunctionalToolWrapperOptionFunc {
+	return func(opts *FunctionalToolWrapperOptions) {
+		opts.title = title
+	}
+}
+
+// WithFunctionalToolWrapperAnnotations sets all behaviour hints of the tool at once
+func WithFunctionalToolWrapperAnnotations(annotations protocol.ToolAnnotations) FunctionalToolWrapperOptionFunc {
+	return func(opts *FunctionalToolWrapperOptions) {
+		opts.annotations = &annotations
+	}
+}
+
+// WithFunctionalToolWrapperReadOnlyHint marks whether the tool does not modify its environment
+func WithFunctionalToolWrapperReadOnlyHint(readOnly bool) FunctionalToolWrapperOptionFunc {
+	return func(opts *FunctionalToolWrapperOptions) {
+		opts.ensureAnnotations().ReadOnlyHint = &readOnly
+	}
+}
+
+// WithFunctionalToolWrapperDestructiveHint marks whether the tool may perform destructive updates
+func WithFunctionalToolWrapperDestructiveHint(destructive bool) FunctionalToolWrapperOptionFunc {
+	return func(opts *FunctionalToolWrapperOptions) {
+		opts.ensureAnnotations().DestructiveHint = &destructive
+	}
+}
+
+// WithFunctionalToolWrapperIdempotentHint marks whether repeated calls with the same arguments have no additional effect
+func WithFunctionalToolWrapperIdempotentHint(idempotent bool) FunctionalToolWrapperOptionFunc {
+	return func(opts *FunctionalToolWrapperOptions) {
+		opts.ensureAnnotations().IdempotentHint = &idempotent
+	}
+}
+
+// WithFunctionalToolWrapperOpenWorldHint marks whether the tool interacts with external entities
+func WithFunctionalToolWrapperOpenWorldHint(openWorld bool) FunctionalToolWrapperOptionFunc {
+	return func(opts *FunctionalToolWrapperOptions) {
+		opts.ensureAnnotations().OpenWorldHint = &openWorld
+	}
+}
+
+func (x *FunctionalToolWrapperOptions) ensureAnnotations() *protocol.ToolAnnotations {
+	if x.annotations == nil {
+		x.annotations = &protocol.ToolAnnotations{}
+	}
+	return x.annotations
+}
+
 func defaultFunctionalToolWrapperOptions() FunctionalToolWrapperOptions {
 	return FunctionalToolWrapperOptions{
 		decodeFunc: func(argsJSON json.RawMessage, receiver any) error {
@@ -98,6 +167,10 @@ func (x *FunctionalToolWrapper[T]) Name() string {
 	return x.name
 }
 
+func (x *FunctionalToolWrapper[T]) Title() string {
+	return x.options.title
+}
+
 func (x *FunctionalToolWrapper[T]) Description() string {
 	return x.description
 }
@@ -106,14 +179,122 @@ func (x *FunctionalToolWrapper[T]) Schema() *jsonschema.Definition {
 	return x.schema
 }
 
+func (x *FunctionalToolWrapper[T]) OutputSchema() *jsonschema.Definition {
+	return nil
+}
+
+func (x *FunctionalToolWrapper[T]) Annotations() *protocol.ToolAnnotations {
+	return x.options.annotations
+}
+
 func (x *FunctionalToolWrapper[T]) Call(ctx context.Context, argsJSON json.RawMessage) ([]protocol.Content, error) {
 	var args T
 	if err := x.options.decodeFunc(argsJSON, &args); err != nil {
-		return nil, err
+		return nil, NewToolCallError(protocol.NewInvalidParamsError("invalid arguments of tool %s: %w", x.name, err))
 	}
 	return x.fn(ctx, args)
 }
 
+type StructuredToolsCallbackFunc[In any, Out any] func(ctx context.Context, args In) (Out, error)
+
+// StructuredToolWrapper wraps a typed function as a tool that returns structured content described by an output schema
+type StructuredToolWrapper[In any, Out any] struct {
+	*FunctionalToolWrapper[In]
+	outputSchema *jsonschema.Definition
+}
+
+func NewStructuredToolWrapper[In any, Out any](name string, description string, fn StructuredToolsCallbackFunc[In, Out],
+	opts ...IFunctionalToolWrapperOption,
+) *StructuredToolWrapper[In, Out] { //nolint:whitespace
+	var zero Out
+
+	x := &StructuredToolWrapper[In, Out]{
+		outputSchema: mustGenOutputSchema(zero),
+	}
+	x.FunctionalToolWrapper = NewFunctionalToolWrapper(name, description, func(ctx context.Context, args In) ([]protocol.Content, error) {
+		out, err := fn(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return x.structuredContent(ctx, out)
+	}, opts...)
+	return x
+}
+
+func mustGenOutputSchema(output any) *jsonschema.Definition {
+	schema := mustGenSchema(output)
+	if schema.Type != jsonschema.Object {
+		panic(fmt.Sprintf("Output type must be a struct, got %s", schema.Type))
+	}
+	return schema
+}
+
+func (x *StructuredToolWrapper[In, Out]) OutputSchema() *jsonschema.Definition {
+	return x.outputSchema
+}
+
+func (x *StructuredToolWrapper[In, Out]) structuredContent(ctx context.Context, out Out) ([]protocol.Content, error) {
+	bs, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	var data any
+	if err := json.Unmarshal(bs, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal output: %w", err)
+	}
+	// nil slices and maps are marshaled as null, render them empty as their schema expects
+	data, filled := fillNullContainers(*x.outputSchema, data)
+	if !jsonschema.Validate(*x.outputSchema, data) {
+		return nil, fmt.Errorf("output of tool %s does not match its output schema", x.Name())
+	}
+	if filled {
+		bs, err = json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output: %w", err)
+		}
+	}
+
+	if err := SetStructuredContent(ctx, json.RawMessage(bs)); err != nil {
+		return nil, err
+	}
+	return []protocol.Content{
+		protocol.NewTextContent(string(bs), nil),
+	}, nil
+}
+
+// fillNullContainers replaces the null values whose schema is an array or an object with empty ones,
+// it reports whether any value was replaced
+func fillNullContainers(schema jsonschema.Definition, data any) (any, bool) {
+	filled := false
+	switch v := data.(type) {
+	case nil:
+		switch schema.Type {
+		case jsonschema.Array:
+			return []any{}, true
+		case jsonschema.Object:
+			return map[string]any{}, true
+		}
+	case map[string]any:
+		for key, value := range v {
+			if property, ok := schema.Properties[key]; ok {
+				var f bool
+				v[key], f = fillNullContainers(property, value)
+				filled = filled || f
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, value := range v {
+				var f bool
+				v[i], f = fillNullContainers(*schema.Items, value)
+				filled = filled || f
+			}
+		}
+	}
+	return data, filled
+}
+
 type FunctionalTools struct {
 	tools       map[string]IToolUnit
 	toolsDefine []protocol.Tool
@@ -136,7 +317,7 @@ func (x *FunctionalTools) Call(ctx context.Context, name string, argsJSON json.R
 			return tool.Call(ctx, argsJSON)
 		}
 	}
-	return nil, fmt.Errorf("tool %s not found", name)
+	return nil, NewToolCallError(protocol.NewToolNotFoundError(name))
 }
 
 func (x *FunctionalTools) StartWatchListChanged(_ context.Context, _ string, _ chan<- protocol.ToolListChangedNotification) error {
@@ -162,6 +343,15 @@ func NewFunctionalToolsBuilder(tools ...IToolUnit) *FunctionalToolsBuilder {
 					Description: tool.Description(),
 					InputSchema: tool.Schema(),
 				}
+				if t, ok := tool.(IToolTitle); ok {
+					toolsDefine[i].Title = t.Title()
+				}
+				if t, ok := tool.(IToolOutputSchema); ok {
+					toolsDefine[i].OutputSchema = t.OutputSchema()
+				}
+				if t, ok := tool.(IToolAnnotations); ok {
+					toolsDefine[i].Annotations = t.Annotations()
+				}
 			}
 			return toolsDefine
 		}(),
@@ -0,0 +1,145 @@
+package iface
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mcp4go/mcp4go/protocol"
+	"github.com/mcp4go/mcp4go/protocol/jsonschema"
+)
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+type weatherOutput struct {
+	Temperature float64  `json:"temperature"`
+	Conditions  []string `json:"conditions"`
+}
+
+func TestStructuredToolWrapper(t *testing.T) {
+	tool := NewStructuredToolWrapper("weather", "get weather",
+		func(_ context.Context, args weatherArgs) (weatherOutput, error) {
+			if args.City == "nowhere" {
+				return weatherOutput{}, nil
+			}
+			return weatherOutput{Temperature: 22.5, Conditions: []string{"sunny"}}, nil
+		},
+	)
+
+	schema := tool.OutputSchema()
+	if schema == nil || schema.Type != jsonschema.Object {
+		t.Fatalf("unexpected output schema: %+v", schema)
+	}
+	if _, ok := schema.Properties["temperature"]; !ok {
+		t.Errorf("output schema misses temperature: %+v", schema.Properties)
+	}
+
+	tools := NewFunctionalToolsBuilder(tool).Build()
+	list, _, _ := tools.List(context.Background(), "")
+	if len(list) != 1 || list[0].OutputSchema != schema {
+		t.Fatalf("output schema not exposed in tool definition: %+v", list)
+	}
+
+	ctx, ext := NewResultContext(context.Background())
+	content, err := tools.Call(ctx, "weather", json.RawMessage(`{"city":"paris"}`))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	want := `{"temperature":22.5,"conditions":["sunny"]}`
+	if string(ext.StructuredContent()) != want {
+		t.Errorf("unexpected structured content: %s", ext.StructuredContent())
+	}
+	if len(content) != 1 || content[0].Text != want {
+		t.Errorf("unexpected text rendering: %+v", content)
+	}
+
+	// a nil slice is rendered as an empty array to match the array schema
+	ctx, ext = NewResultContext(context.Background())
+	if _, err := tools.Call(ctx, "weather", json.RawMessage(`{"city":"nowhere"}`)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if want := `{"conditions":[],"temperature":0}`; string(ext.StructuredContent()) != want {
+		t.Errorf("unexpected structured content: %s", ext.StructuredContent())
+	}
+}
+
+func TestSetStructuredContentWithoutResultContext(t *testing.T) {
+	if err := SetStructuredContent(context.Background(), map[string]string{}); err != ErrNoResultContext {
+		t.Errorf("expected ErrNoResultContext, got %v", err)
+	}
+}
+
+func TestFunctionalToolsBuilderDefinitions(t *testing.T) {
+	tool := NewFunctionalToolWrapper("search", "search documents",
+		func(_ context.Context, _ weatherArgs) ([]protocol.Content, error) {
+			return nil, nil
+		},
+		WithFunctionalToolWrapperTitle("Search Documents"),
+		WithFunctionalToolWrapperReadOnlyHint(true),
+	)
+
+	list, _, _ := NewFunctionalToolsBuilder(tool).Build().List(context.Background(), "")
+	if len(list) != 1 {
+		t.Fatalf("expected 1 tool, got %d", len(list))
+	}
+	if list[0].Title != "Search Documents" {
+		t.Errorf("unexpected title: %s", list[0].Title)
+	}
+	if !list[0].Annotations.IsReadOnly() {
+		t.Errorf("unexpected annotations: %+v", list[0].Annotations)
+	}
+	if list[0].OutputSchema != nil {
+		t.Errorf("unexpected output schema: %+v", list[0].OutputSchema)
+	}
+}
+
+// plainTool only implements IToolUnit, as tools written before titles and annotations
+type plainTool struct{}
+
+func (plainTool) Name() string        { return "plain" }
+func (plainTool) Description() string { return "plain tool" }
+func (plainTool) Schema() *jsonschema.Definition {
+	return &jsonschema.Definition{Type: jsonschema.Object}
+}
+func (plainTool) Call(context.Context, json.RawMessage) ([]protocol.Content, error) {
+	return nil, nil
+}
+
+func TestFunctionalToolsBuilderPlainTool(t *testing.T) {
+	list, _, _ := NewFunctionalToolsBuilder(plainTool{}).Build().List(context.Background(), "")
+	if len(list) != 1 || list[0].Name != "plain" {
+		t.Fatalf("unexpected tools: %+v", list)
+	}
+	if list[0].Title != "" || list[0].OutputSchema != nil || list[0].Annotations != nil {
+		t.Errorf("unexpected optional fields: %+v", list[0])
+	}
+}
+
+func TestFunctionalToolsErrors(t *testing.T) {
+	tool := NewFunctionalToolWrapper("weather", "get weather",
+		func(_ context.Context, args weatherArgs) ([]protocol.Content, error) {
+			return nil, NewToolErrorText("unknown city " + args.City)
+		},
+	)
+	tools := NewFunctionalToolsBuilder(tool).Build()
+
+	_, err := tools.Call(context.Background(), "missing", json.RawMessage(`{}`))
+	if code, _ := protocol.ErrorCode(err); code != protocol.ErrorCodeToolNotFound {
+		t.Errorf("expected tool not found, got: %v", err)
+	}
+
+	_, err = tools.Call(context.Background(), "weather", json.RawMessage(`{"city":1}`))
+	var callErr *ToolCallError
+	if code, _ := protocol.ErrorCode(err); code != protocol.ErrorCodeInvalidParams || !errors.As(err, &callErr) {
+		t.Errorf("expected invalid params rejecting the call, got: %v", err)
+	}
+
+	_, err = tools.Call(context.Background(), "weather", json.RawMessage(`{"city":"atlantis"}`))
+	toolErr, ok := err.(*ToolError)
+	if !ok || toolErr.Error() != "unknown city atlantis" || len(toolErr.Content()) != 1 {
+		t.Errorf("expected tool error, got: %v", err)
+	}
+}
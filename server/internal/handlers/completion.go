@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mcp4go/mcp4go/protocol"
+	"github.com/mcp4go/mcp4go/server/iface"
+)
+
+// Handle completion/complete request
+type CompleteHandler struct {
+	completion iface.ICompletion
+	decodeFn   RequestDecodeFunc
+}
+
+// Create a new instance
+func NewCompleteHandler(completion iface.ICompletion, decodeFn RequestDecodeFunc) *CompleteHandler {
+	return &CompleteHandler{
+		completion: completion,
+		decodeFn:   decodeFn,
+	}
+}
+
+// Handle completion/complete request
+func (x *CompleteHandler) Handle(ctx context.Context, message json.RawMessage) (json.RawMessage, error) {
+	var req protocol.CompleteRequest
+	err := x.decodeFn(message, &req)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	var arguments map[string]string
+	if req.Context != nil {
+		arguments = req.Context.Arguments
+	}
+
+	ctx, ext := iface.NewResultContext(ctx)
+	completion, err := x.completion.Complete(ctx, req.Ref, req.Argument, arguments)
+	if err != nil {
+		return nil, wrapError(err, protocol.NewInternalError, "complete failed")
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+
+	result := protocol.CompleteResult{
+		Completion: completion,
+		Meta:       ext.Meta(),
+	}
+
+	return json.Marshal(result)
+}
+
+// Returns the result
+func (x *CompleteHandler) Method() protocol.McpMethod {
+	return protocol.MethodComplete
+}
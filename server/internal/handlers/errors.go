@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// invalidParams reports params that could not be decoded
+func invalidParams(err error) error {
+	return protocol.NewInvalidParamsError("invalid params: %w", err)
+}
+
+// wrapError wraps a failure of user code with the given error constructor,
+// an error that already carries a protocol.Error keeps its code
+func wrapError(err error, newError func(format string, args ...interface{}) *protocol.Error, msg string) error {
+	if _, ok := protocol.ErrorCode(err); ok {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return newError("%s: %w", msg, err)
+}
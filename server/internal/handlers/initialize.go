@@ -36,7 +36,7 @@ func (x *InitializeHandler) Handle(_ context.Context, message json.RawMessage) (
 	var req protocol.InitializeRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
 	// Build initialization response
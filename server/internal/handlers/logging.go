@@ -3,14 +3,18 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 
 	"github.com/mcp4go/mcp4go/protocol"
 	"github.com/mcp4go/mcp4go/server/iface"
 )
 
 // Handle logging/setLevel request
+// A handler is created for each session, so the level set by a client does not affect the others
 type SetLevelHandler struct {
 	// Current log level
+	mu           sync.RWMutex
 	currentLevel protocol.LoggingLevel
 	decodeFn     RequestDecodeFunc
 }
@@ -28,11 +32,16 @@ func (x *SetLevelHandler) Handle(_ context.Context, message json.RawMessage) (js
 	var req protocol.SetLevelRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
+	}
+	if _, ok := LogLevelMap[req.Level]; !ok {
+		return nil, protocol.NewInvalidParamsError("invalid params: unknown logging level %q", req.Level)
 	}
 
 	// Set new log level
+	x.mu.Lock()
 	x.currentLevel = req.Level
+	x.mu.Unlock()
 
 	result := protocol.SetLevelResult{}
 	return json.Marshal(result)
@@ -45,6 +54,8 @@ func (x *SetLevelHandler) Method() protocol.McpMethod {
 
 // Get the specified data
 func (x *SetLevelHandler) GetCurrentLevel() protocol.LoggingLevel {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
 	return x.currentLevel
 }
 
@@ -80,22 +91,35 @@ var LogLevelMap = map[protocol.LoggingLevel]int{
 
 // SendLogMessage sends log messages, but won't send if the message level is below the current level setting
 func (x *LoggingMessageSender) SendLogMessage(level protocol.LoggingLevel, logger string, data interface{}) error {
-	// Check if the message level should be sent
-	currentLevel := x.levelHandler.GetCurrentLevel()
+	return x.Log(context.Background(), level, logger, data)
+}
 
-	if LogLevelMap[level] < LogLevelMap[currentLevel] {
+// Log sends a log message unless its level is below the current level setting, it gives up when ctx is done
+func (x *LoggingMessageSender) Log(ctx context.Context, level protocol.LoggingLevel, logger string, data interface{}) error {
+	if !x.Enabled(level) {
 		// Message level is below the current level, don't send
 		return nil
 	}
 
-	bs, _ := json.Marshal(data)
-	x.logChan <- protocol.LoggingMessageNotification{
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log data: %w", err)
+	}
+	select {
+	case x.logChan <- protocol.LoggingMessageNotification{
 		Level:  level,
 		Logger: logger,
 		Data:   bs,
+	}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	return nil
+// Enabled reports whether messages of the given level are sent with the current level setting
+func (x *LoggingMessageSender) Enabled(level protocol.LoggingLevel) bool {
+	return LogLevelMap[level] >= LogLevelMap[x.levelHandler.GetCurrentLevel()]
 }
 
 // Convenience methods for sending logs at different levels
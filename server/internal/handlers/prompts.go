@@ -3,7 +3,6 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"github.com/mcp4go/mcp4go/protocol"
 	"github.com/mcp4go/mcp4go/server/iface"
@@ -28,17 +27,19 @@ func (x *ListPromptsHandler) Handle(ctx context.Context, message json.RawMessage
 	var req protocol.ListPromptsRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
+	ctx, ext := iface.NewResultContext(ctx)
 	prompts, nextCursor, err := x.prompt.List(ctx, req.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("list prompts failed: %w", err)
+		return nil, wrapError(err, protocol.NewInternalError, "list prompts failed")
 	}
 
 	result := protocol.ListPromptsResult{
 		Prompts:    prompts,
 		NextCursor: nextCursor,
+		Meta:       ext.Meta(),
 	}
 
 	return json.Marshal(result)
@@ -68,17 +69,19 @@ func (x *GetPromptHandler) Handle(ctx context.Context, message json.RawMessage)
 	var req protocol.GetPromptRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
+	ctx, ext := iface.NewResultContext(ctx)
 	description, messages, err := x.prompt.Get(ctx, req.Name, req.Arguments)
 	if err != nil {
-		return nil, fmt.Errorf("get prompt failed: %w", err)
+		return nil, wrapError(err, protocol.NewPromptExecutionError, "get prompt failed")
 	}
 
 	result := protocol.GetPromptResult{
 		Description: description,
 		Messages:    messages,
+		Meta:        ext.Meta(),
 	}
 
 	return json.Marshal(result)
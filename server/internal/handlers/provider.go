@@ -1,21 +1,28 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/google/wire"
 
+	"github.com/mcp4go/mcp4go/server/iface"
 	"github.com/mcp4go/mcp4go/server/internal/router"
 )
 
 type RequestDecodeFunc func(data json.RawMessage, v any) error
 
+// ExtraHandlers are registered after the built-in handlers, so they override the ones with the same method
+type ExtraHandlers []router.IHandler
+
 var Provider = wire.NewSet(
 	NewIHandlers,
 	NewInitializedHandler,
 	NewListPromptsHandler, NewGetPromptHandler,
 	NewListResourcesHandler, NewReadResourceHandler, NewListResourceTemplatesHandler, NewSubscribeHandler, NewUnsubscribeHandler,
 	NewListToolsHandler, NewCallToolHandler,
+	NewCompleteHandler,
+	NewLoggingMessageSender,
 )
 
 func NewIHandlers(
@@ -31,9 +38,12 @@ func NewIHandlers(
 	unsubscribeHandler *UnsubscribeHandler,
 	listToolsHandler *ListToolsHandler,
 	callToolHandler *CallToolHandler,
+	completeHandler *CompleteHandler,
+	loggingMessageSender *LoggingMessageSender,
+	extraHandlers ExtraHandlers,
 ) []router.IHandler {
 	//nolint:whitespace
-	return []router.IHandler{
+	list := []router.IHandler{
 		initializeHandler,
 		initializedHandler,
 		setLevelHandler,
@@ -46,5 +56,21 @@ func NewIHandlers(
 		unsubscribeHandler,
 		listToolsHandler,
 		callToolHandler,
+		completeHandler,
+	}
+	list = append(list, extraHandlers...)
+	for i, handler := range list {
+		list[i] = &clientLoggerHandler{IHandler: handler, logger: loggingMessageSender}
 	}
+	return list
+}
+
+// clientLoggerHandler exposes the logger of the session to the implementations called by a handler
+type clientLoggerHandler struct {
+	router.IHandler
+	logger iface.IClientLogger
+}
+
+func (x *clientLoggerHandler) Handle(ctx context.Context, message json.RawMessage) (json.RawMessage, error) {
+	return x.IHandler.Handle(iface.NewClientLoggerContext(ctx, x.logger), message)
 }
@@ -3,7 +3,6 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"github.com/mcp4go/mcp4go/protocol"
 	"github.com/mcp4go/mcp4go/server/iface"
@@ -28,18 +27,19 @@ func (x *ListResourcesHandler) Handle(ctx context.Context, message json.RawMessa
 	var req protocol.ListResourcesRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
+	ctx, ext := iface.NewResultContext(ctx)
 	resources, nextCursor, err := x.resource.List(ctx, req.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("list resources failed: %w", err)
+		return nil, wrapError(err, protocol.NewInternalError, "list resources failed")
 	}
 
 	result := protocol.ListResourcesResult{
 		Resources:  resources,
 		NextCursor: nextCursor,
-		Meta:       nil,
+		Meta:       ext.Meta(),
 	}
 
 	return json.Marshal(result)
@@ -70,17 +70,19 @@ func (x *ReadResourceHandler) Handle(ctx context.Context, message json.RawMessag
 	var req protocol.ReadResourceRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
 	// Read the resource content based on the URI
+	ctx, ext := iface.NewResultContext(ctx)
 	contents, err := x.resource.Query(ctx, req.URI)
 	if err != nil {
-		return nil, fmt.Errorf("query resources failed: %w", err)
+		return nil, wrapError(err, protocol.NewResourceReadError, "query resources failed")
 	}
 
 	result := protocol.ReadResourceResult{
 		Contents: contents,
+		Meta:     ext.Meta(),
 	}
 
 	return json.Marshal(result)
@@ -111,7 +113,7 @@ func (x *ListResourceTemplatesHandler) Handle(_ context.Context, message json.Ra
 	var req protocol.ListResourceTemplatesRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
 	// Example resource template list, in real applications this should be retrieved from a service or database
@@ -155,16 +157,19 @@ func (x *SubscribeHandler) Handle(ctx context.Context, message json.RawMessage)
 	var req protocol.SubscribeRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
 	// Here should implement the actual subscription logic, such as adding the URI to the subscription list
 	// In this example, we just return a success response
+	ctx, ext := iface.NewResultContext(ctx)
 	err = x.resource.Watch(ctx, req.URI, x.ch)
 	if err != nil {
-		return nil, fmt.Errorf("subscribe failed: %w", err)
+		return nil, wrapError(err, protocol.NewResourceSubscribeError, "subscribe failed")
+	}
+	result := protocol.SubscribeResult{
+		Meta: ext.Meta(),
 	}
-	result := protocol.SubscribeResult{}
 	return json.Marshal(result)
 }
 
@@ -192,17 +197,20 @@ func (x *UnsubscribeHandler) Handle(ctx context.Context, message json.RawMessage
 	var req protocol.UnsubscribeRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
 	// Here should implement the actual unsubscribe logic, such as removing the URI from the subscription list
 	// In this example, we just return a success response
+	ctx, ext := iface.NewResultContext(ctx)
 	err = x.resource.CloseWatch(ctx, req.URI)
 	if err != nil {
-		return nil, fmt.Errorf("unsubscribe failed: %w", err)
+		return nil, wrapError(err, protocol.NewResourceUnsubscribeError, "unsubscribe failed")
 	}
 
-	result := protocol.UnsubscribeResult{}
+	result := protocol.UnsubscribeResult{
+		Meta: ext.Meta(),
+	}
 	return json.Marshal(result)
 }
 
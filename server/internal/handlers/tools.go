@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/mcp4go/mcp4go/protocol"
@@ -28,17 +29,19 @@ func (x *ListToolsHandler) Handle(ctx context.Context, message json.RawMessage)
 	var req protocol.ListToolsRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
+	ctx, ext := iface.NewResultContext(ctx)
 	tools, nextCursor, err := x.tool.List(ctx, req.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("list tools failed: %w", err)
+		return nil, wrapError(err, protocol.NewInternalError, "list tools failed")
 	}
 
 	result := protocol.ListToolsResult{
 		Tools:      tools,
 		NextCursor: nextCursor,
+		Meta:       ext.Meta(),
 	}
 
 	return json.Marshal(result)
@@ -68,16 +71,28 @@ func (x *CallToolHandler) Handle(ctx context.Context, message json.RawMessage) (
 	var req protocol.CallToolRequest
 	err := x.decodeFn(message, &req)
 	if err != nil {
-		return nil, err
+		return nil, invalidParams(err)
 	}
 
+	ctx, ext := iface.NewResultContext(ctx)
 	content, err := x.tool.Call(ctx, req.Name, req.Arguments)
 	if err != nil {
-		return nil, fmt.Errorf("call tool failed: %w", err)
+		// unknown tools and invalid arguments are protocol errors,
+		// any other failure is reported in the result so that the model can see it
+		var toolErr *iface.ToolError
+		if errors.As(err, &toolErr) {
+			content = toolErr.Content()
+		} else if isCallToolProtocolError(err) {
+			return nil, fmt.Errorf("call tool failed: %w", err)
+		} else {
+			content = []protocol.Content{protocol.NewTextContent(err.Error(), nil)}
+		}
 	}
 	result := protocol.CallToolResult{
-		IsError: err != nil,
-		Content: content,
+		IsError:           err != nil,
+		Content:           content,
+		StructuredContent: ext.StructuredContent(),
+		Meta:              ext.Meta(),
 	}
 
 	return json.Marshal(result)
@@ -87,3 +102,10 @@ func (x *CallToolHandler) Handle(ctx context.Context, message json.RawMessage) (
 func (x *CallToolHandler) Method() protocol.McpMethod {
 	return protocol.MethodCallTool
 }
+
+// isCallToolProtocolError reports whether a tool call was rejected before the tool ran, the protocol errors
+// returned by the tool itself are execution failures
+func isCallToolProtocolError(err error) bool {
+	var callErr *iface.ToolCallError
+	return errors.As(err, &callErr)
+}
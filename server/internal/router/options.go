@@ -0,0 +1,21 @@
+package router
+
+import (
+	"context"
+	"time"
+)
+
+// Options configures the behaviour of a router
+type Options struct {
+	// KeepaliveInterval is the interval between two pings sent to the client, zero disables the keepalive
+	KeepaliveInterval time.Duration
+	// KeepaliveMaxMissed is the number of consecutive unanswered pings after which the connection is torn down
+	KeepaliveMaxMissed int
+	// OnKeepaliveTimeout is called with ErrKeepaliveTimeout when the connection is torn down for missed pings
+	OnKeepaliveTimeout func(ctx context.Context, err error)
+	// RequestTimeout is the maximum time spent waiting for the response of a request sent to the client,
+	// zero only relies on the context of the request. Elicitation waits for the user and is never bounded by it
+	RequestTimeout time.Duration
+	// OnRootsListChanged is called when the client notifies its roots changed, after the cached roots are dropped
+	OnRootsListChanged func(ctx context.Context)
+}
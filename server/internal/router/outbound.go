@@ -0,0 +1,204 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mcp4go/mcp4go/protocol"
+	"github.com/mcp4go/mcp4go/server/iface"
+)
+
+// ErrKeepaliveTimeout ends a session whose client missed too many pings
+var ErrKeepaliveTimeout = errors.New("keepalive timeout: client did not answer pings")
+
+// cancelNotificationTimeout bounds the time spent telling the client a request was cancelled
+const cancelNotificationTimeout = time.Second
+
+// request sends a request to the client and waits for its response, an error response is returned as *iface.ClientRequestError
+// It gives up after the configured request timeout, if any, with an error wrapping context.DeadlineExceeded
+func (x *Router) request(ctx context.Context, method protocol.McpMethod, params interface{}, result interface{}) error {
+	paramsBs := json.RawMessage("{}")
+	if params != nil {
+		var err error
+		paramsBs, err = json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+	}
+
+	parent := ctx
+	var cancel context.CancelFunc
+	if timeout := x.requestTimeout(method); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	id := json.RawMessage(strconv.FormatInt(x.requestID.Add(1), 10))
+	key, _ := requestIDKey(id)
+	responseCh := make(chan *protocol.JsonrpcResponse, 1)
+	x.pendingResp.Store(key, responseCh)
+	defer x.pendingResp.Delete(key)
+
+	select {
+	case x.writePackCH <- (*protocol.JsonrpcPack)(protocol.NewJsonrpcRequest(id, method, paramsBs)):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case resp := <-responseCh:
+		if resp.Error != nil {
+			return iface.NewClientRequestError(method, protocol.NewErrorFromJsonrpc(resp.Error))
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("failed to unmarshal result: %w", err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		x.notifyCancelled(id, ctx.Err())
+		if parent.Err() == nil {
+			return fmt.Errorf("request %s timed out after %s: %w", method, x.requestTimeout(method), ctx.Err())
+		}
+		return ctx.Err()
+	}
+}
+
+// requestTimeout returns the time given to the client to answer a request, zero if unbounded
+// Elicitation waits for the user to answer, only the context of the request can end it
+func (x *Router) requestTimeout(method protocol.McpMethod) time.Duration {
+	if method == protocol.MethodElicit {
+		return 0
+	}
+	return x.options.RequestTimeout
+}
+
+// clientSession exposes the client of the session to handlers
+type clientSession struct {
+	router *Router
+}
+
+func (x clientSession) ClientInfo() protocol.Implementation {
+	info, _ := x.router.session.client()
+	return info
+}
+
+func (x clientSession) ClientCapabilities() protocol.ClientCapabilities {
+	_, caps := x.router.session.client()
+	return caps
+}
+
+func (x clientSession) ListRoots(ctx context.Context) ([]protocol.Root, error) {
+	return x.router.listRoots(ctx)
+}
+
+func (x clientSession) Request(ctx context.Context, method protocol.McpMethod, params interface{}, result interface{}) error {
+	return x.router.request(ctx, method, params, result)
+}
+
+// notifyCancelled tells the client to stop processing a request, it never blocks the caller for long
+func (x *Router) notifyCancelled(id json.RawMessage, reason error) {
+	bs, _ := json.Marshal(protocol.CancelledNotification{
+		RequestID: id,
+		Reason:    reason.Error(),
+	})
+	select {
+	case x.writePackCH <- (*protocol.JsonrpcPack)(protocol.NewJsonrpcNotification(protocol.NotificationCancelled, bs)):
+	case <-time.After(cancelNotificationTimeout):
+	}
+}
+
+// decodeResponse decodes a message if it is a response to a request sent by the router
+func decodeResponse(message json.RawMessage) (*protocol.JsonrpcResponse, bool) {
+	var resp protocol.JsonrpcResponse
+	if err := json.Unmarshal(message, &resp); err != nil {
+		return nil, false
+	}
+	if resp.Method != "" || len(resp.ID) == 0 || (len(resp.Result) == 0 && resp.Error == nil) {
+		return nil, false
+	}
+	return &resp, true
+}
+
+// deliverResponse hands a response to the request waiting for it, IDs are matched whatever their formatting
+// A response with an invalid jsonrpc version, or echoing the numeric ID of the request as a string,
+// fails the request with an invalid request error
+func (x *Router) deliverResponse(ctx context.Context, resp *protocol.JsonrpcResponse) {
+	var rpcErr *protocol.Error
+	key, ok := requestIDKey(resp.ID)
+	if !ok {
+		x.log.Warnf(ctx, "[Router] response with invalid id %s\n", resp.ID)
+		return
+	}
+	ch, ok := x.pendingResp.LoadAndDelete(key)
+	if !ok {
+		if key, isNumber := numberStringKey(resp.ID); isNumber {
+			ch, ok = x.pendingResp.LoadAndDelete(key)
+			rpcErr = protocol.NewInvalidRequestError("response id %s does not match the request id %s", resp.ID, key)
+		}
+	}
+	if !ok {
+		x.log.Warnf(ctx, "[Router] response to unknown request %s\n", resp.ID)
+		return
+	}
+	if resp.Jsonrpc != protocol.JSONRPCVersion {
+		rpcErr = protocol.NewInvalidRequestError("invalid jsonrpc version %q of response", resp.Jsonrpc)
+	}
+	if rpcErr != nil {
+		x.log.Errorf(ctx, "[Router] %v\n", rpcErr)
+		resp = protocol.NewJsonrpcResponse(resp.ID, nil, rpcErr.JsonrpcError())
+	}
+	ch.(chan *protocol.JsonrpcResponse) <- resp
+}
+
+// keepalive pings the client at the configured interval and ends the session after too many missed pings
+func (x *Router) keepalive(ctx context.Context) error {
+	ticker := time.NewTicker(x.options.KeepaliveInterval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, x.options.KeepaliveInterval)
+		err := x.request(pingCtx, protocol.MethodPing, nil, nil)
+		cancel()
+
+		// any response, even an error, proves the client is alive
+		var clientErr *iface.ClientRequestError
+		if err == nil || errors.As(err, &clientErr) {
+			missed = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		missed++
+		x.log.Warnf(ctx, "[Router][keepalive] ping missed (%d/%d): %v\n", missed, x.options.KeepaliveMaxMissed, err)
+		if missed >= x.options.KeepaliveMaxMissed {
+			if x.options.OnKeepaliveTimeout != nil {
+				x.options.OnKeepaliveTimeout(ctx, ErrKeepaliveTimeout)
+			}
+			return ErrKeepaliveTimeout
+		}
+	}
+}
+
+// pingHandler answers ping requests
+func (x *Router) pingHandler() IHandlerFunc {
+	return func(_ context.Context, _ json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage("{}"), nil
+	}
+}
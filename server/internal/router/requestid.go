@@ -0,0 +1,57 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// requestIDKey returns the canonical JSON of a request ID, so that an ID is matched whatever its formatting
+// ok is false if the ID is neither a string nor a number
+func requestIDKey(id json.RawMessage) (key string, ok bool) {
+	decoder := json.NewDecoder(bytes.NewReader(id))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", false
+	}
+
+	switch v := v.(type) {
+	case string:
+		bs, _ := json.Marshal(v)
+		return string(bs), true
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return strconv.FormatInt(i, 10), true
+		}
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// processingKey returns the key of a request being processed, invalid IDs are kept as they are
+func processingKey(id json.RawMessage) string {
+	if key, ok := requestIDKey(id); ok {
+		return key
+	}
+	return string(id)
+}
+
+// numberStringKey returns the canonical key of a number ID echoed as a string, such as "1" for 1
+func numberStringKey(id json.RawMessage) (string, bool) {
+	var s string
+	if err := json.Unmarshal(id, &s); err != nil {
+		return "", false
+	}
+	key, ok := requestIDKey(json.RawMessage(s))
+	if !ok || strings.HasPrefix(key, `"`) {
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,72 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// rootsCache keeps the roots of the client until it notifies they changed
+type rootsCache struct {
+	mu    sync.Mutex
+	roots []protocol.Root
+	valid bool
+	// generation is bumped on invalidation so that a list fetched meanwhile is not cached
+	generation uint64
+}
+
+// get returns the cached roots, fetching them with fetch if the cache is empty
+func (x *rootsCache) get(ctx context.Context, fetch func(ctx context.Context) ([]protocol.Root, error)) ([]protocol.Root, error) {
+	x.mu.Lock()
+	if x.valid {
+		roots := append([]protocol.Root(nil), x.roots...)
+		x.mu.Unlock()
+		return roots, nil
+	}
+	generation := x.generation
+	x.mu.Unlock()
+
+	roots, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if generation == x.generation {
+		x.roots = append([]protocol.Root(nil), roots...)
+		x.valid = true
+	}
+	return roots, nil
+}
+
+func (x *rootsCache) invalidate() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.roots = nil
+	x.valid = false
+	x.generation++
+}
+
+// listRoots returns the roots of the client, cached until the client notifies they changed
+func (x *Router) listRoots(ctx context.Context) ([]protocol.Root, error) {
+	return x.roots.get(ctx, func(ctx context.Context) ([]protocol.Root, error) {
+		var result protocol.ListRootsResult
+		if err := x.request(ctx, protocol.MethodListRoots, protocol.ListRootsRequest{}, &result); err != nil {
+			return nil, err
+		}
+		return result.Roots, nil
+	})
+}
+
+func (x *Router) rootsListChangedHandler() IHandlerFunc {
+	return func(ctx context.Context, _ json.RawMessage) (json.RawMessage, error) {
+		x.roots.invalidate()
+		if x.options.OnRootsListChanged != nil {
+			x.options.OnRootsListChanged(ctx)
+		}
+		return nil, nil
+	}
+}
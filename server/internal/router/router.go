@@ -1,6 +1,7 @@
 package router
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"io"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ccheers/xpkg/generic/arrayx"
 	"github.com/ccheers/xpkg/sync/errgroup"
@@ -50,27 +52,51 @@ func (x *IHandlerFuncWrapper) Method() protocol.McpMethod {
 type Router struct {
 	log *logger.LogHelper
 
-	writePackCH chan *protocol.JsonrpcPack
+	writePackCH  chan *protocol.JsonrpcPack
+	writeBatchCH chan []*protocol.JsonrpcPack
 
 	handlers      map[protocol.McpMethod]IHandler
 	bus           iface.EventBus
 	processingReq sync.Map
+	session       *session
+	options       Options
+
+	// requests sent to the client
+	requestID   atomic.Int64
+	pendingResp sync.Map
+	roots       rootsCache
+}
+
+// processingRequest tracks a request being handled so it can be cancelled by the client
+type processingRequest struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
 }
 
 func NewIRouter(x *Router) IRouter {
 	return x
 }
 
-func NewRouter(list []IHandler, bus iface.EventBus, _logger logger.ILogger) (*Router, error) {
+func NewRouter(list []IHandler, bus iface.EventBus, _logger logger.ILogger, options Options) (*Router, error) {
+	if options.KeepaliveMaxMissed <= 0 {
+		options.KeepaliveMaxMissed = 1
+	}
 	x := &Router{
-		log:         logger.NewLogHelper(_logger),
-		writePackCH: make(chan *protocol.JsonrpcPack, 2048),
-		handlers:    nil,
-		bus:         bus,
+		log:          logger.NewLogHelper(_logger),
+		writePackCH:  make(chan *protocol.JsonrpcPack, 2048),
+		writeBatchCH: make(chan []*protocol.JsonrpcPack, 256),
+		handlers:     nil,
+		bus:          bus,
+		session:      &session{},
+		options:      options,
 	}
 
-	// add canceled handlers
-	list = append(list, NewIHandlerFuncWrapper(x.cancelHandler(), protocol.NotificationCancelled))
+	// add built-in handlers
+	list = append([]IHandler{
+		NewIHandlerFuncWrapper(x.cancelHandler(), protocol.NotificationCancelled),
+		NewIHandlerFuncWrapper(x.pingHandler(), protocol.MethodPing),
+		NewIHandlerFuncWrapper(x.rootsListChangedHandler(), protocol.NotificationRootsListChanged),
+	}, list...)
 
 	x.handlers = arrayx.BuildMap(list, func(t IHandler) protocol.McpMethod {
 		return t.Method()
@@ -87,6 +113,11 @@ func (x *Router) Handle(ctx context.Context, reader io.Reader, writer io.Writer)
 	eg.Go(func(ctx context.Context) error {
 		return x.writeLoop(ctx, writer)
 	})
+	if x.options.KeepaliveInterval > 0 {
+		eg.Go(func(ctx context.Context) error {
+			return x.keepalive(ctx)
+		})
+	}
 	eg.Go(func(ctx context.Context) error {
 		for {
 			select {
@@ -156,14 +187,45 @@ func (x *Router) Handle(ctx context.Context, reader io.Reader, writer io.Writer)
 	return eg.Wait()
 }
 
+// decodedMessage is a message read from the connection, or the error that occurred while reading it
+type decodedMessage struct {
+	message json.RawMessage
+	err     error
+}
+
+// readMessages decodes the messages of the reader in the background, so that the session can end
+// while a read is blocked, the channel is closed after the first error that is not a parse error
+func (x *Router) readMessages(ctx context.Context, reader io.Reader) <-chan decodedMessage {
+	messages := make(chan decodedMessage)
+	go func() {
+		defer close(messages)
+		decoder := protocol.NewMessageDecoder(reader)
+		for {
+			message, err := decoder.Decode()
+			select {
+			case messages <- decodedMessage{message: message, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			var parseErr *protocol.Error
+			if err != nil && !errors.As(err, &parseErr) {
+				return
+			}
+		}
+	}()
+	return messages
+}
+
 func (x *Router) readLoop(ctx context.Context, reader io.Reader) error {
-	decoder := json.NewDecoder(reader)
+	messages := x.readMessages(ctx, reader)
+	defer x.session.shutdown()
 
 	for {
+		var decoded decodedMessage
 		select {
 		case <-ctx.Done():
 			return nil
-		default:
+		case decoded = <-messages:
 		}
 		err := func() error {
 			defer func() {
@@ -172,53 +234,44 @@ func (x *Router) readLoop(ctx context.Context, reader io.Reader) error {
 				}
 			}()
 
-			var req protocol.JsonrpcRequest
-			err := decoder.Decode(&req)
+			message, err := decoded.message, decoded.err
 			if err != nil {
-				return fmt.Errorf("decode error: %w", err)
+				// malformed input is answered and skipped, the session goes on with the next message
+				var parseErr *protocol.Error
+				if errors.As(err, &parseErr) {
+					x.log.Errorf(ctx, "[Router][readLoop] %v\n", err)
+					x.writeError(nil, parseErr)
+					return nil
+				}
+				return err
 			}
 
-			x.log.Debugf(ctx, "#%s. method[%s] params[%s]\n", req.GetID(), req.Method, string(req.Params))
-			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						x.log.Errorf(ctx, "[Router][handle] panic: %v, stack:\n%s\n", r, debug.Stack())
-					}
-				}()
-
-				//nolint:govet
-				ctx, cancel := context.WithCancel(ctx)
-				defer cancel()
-
-				// add cancel callback
-				x.processingReq.Store(string(req.GetID()), cancel)
-				defer x.processingReq.Delete(string(req.GetID()))
-
-				respBs, err := x.handle(ctx, &req)
-				if err != nil {
-					x.log.Errorf(ctx, "handle error: %v\n", err)
-				}
-				if req.IsNotification() {
-					return
+			if isBatch(message) {
+				x.dispatchBatch(ctx, message)
+				return nil
+			}
+
+			if resp, ok := decodeResponse(message); ok {
+				x.deliverResponse(ctx, resp)
+				return nil
+			}
+
+			req, err := decodeRequest(message)
+			if err != nil {
+				x.log.Errorf(ctx, "[Router][readLoop] %v, message=%s\n", err, message)
+				if req == nil || !req.IsNotification() {
+					x.writeError(requestID(req), err)
 				}
-				if err != nil {
-					code := int64(-1)
-					if errCode, ok := err.(interface{ Code() int64 }); ok {
-						code = errCode.Code()
-					}
-					x.writePackCH <- (*protocol.JsonrpcPack)(
-						protocol.NewJsonrpcResponse(req.GetID(), nil, &protocol.JsonrpcError{
-							Code:    code,
-							Message: err.Error(),
-							Data:    nil,
-						}),
-					)
-					return
+				return nil
+			}
+			if err := x.session.admit(req); err != nil {
+				x.log.Errorf(ctx, "[Router][readLoop] %v\n", err)
+				if !req.IsNotification() {
+					x.writeError(req.GetID(), err)
 				}
-				x.writePackCH <- (*protocol.JsonrpcPack)(
-					protocol.NewJsonrpcResponse(req.GetID(), respBs, nil),
-				)
-			}()
+				return nil
+			}
+			x.dispatch(ctx, req)
 			return nil
 		}()
 		if err != nil {
@@ -227,19 +280,214 @@ func (x *Router) readLoop(ctx context.Context, reader io.Reader) error {
 	}
 }
 
+// decodeRequest decodes and validates a single request, the request is returned with the error if it could be decoded
+func decodeRequest(message json.RawMessage) (*protocol.JsonrpcRequest, error) {
+	var req protocol.JsonrpcRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return nil, protocol.NewInvalidRequestError("invalid request: %v", err)
+	}
+	if err := req.Validate(); err != nil {
+		return &req, err
+	}
+	return &req, nil
+}
+
+// requestID returns the id to answer an invalid request with, null if unknown
+func requestID(req *protocol.JsonrpcRequest) json.RawMessage {
+	if req == nil || req.IsNotification() {
+		return json.RawMessage("null")
+	}
+	return req.GetID()
+}
+
+// writeError writes an error response, a nil id is written as null
+func (x *Router) writeError(id json.RawMessage, err error) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	x.writePackCH <- (*protocol.JsonrpcPack)(protocol.NewJsonrpcResponse(id, nil, jsonrpcError(err)))
+}
+
+// dispatch handles a single request in the background and writes its response
+func (x *Router) dispatch(ctx context.Context, req *protocol.JsonrpcRequest) {
+	ctx, processing := x.startProcessing(ctx, req)
+
+	// initialize is handled in order, the messages that follow it depend on its outcome
+	if req.Method == protocol.MethodInitialize {
+		if resp := x.process(ctx, req, processing); resp != nil {
+			x.writePackCH <- resp
+		}
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				x.log.Errorf(ctx, "[Router][handle] panic: %v, stack:\n%s\n", r, debug.Stack())
+			}
+		}()
+
+		if resp := x.process(ctx, req, processing); resp != nil {
+			x.writePackCH <- resp
+		}
+	}()
+}
+
+// dispatchBatch handles the entries of a batch concurrently and writes their responses as one array
+func (x *Router) dispatchBatch(ctx context.Context, message json.RawMessage) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(message, &entries); err != nil || len(entries) == 0 {
+		x.writeError(nil, protocol.NewInvalidRequestError("invalid batch"))
+		return
+	}
+
+	resps := make([]*protocol.JsonrpcPack, len(entries))
+	wg := sync.WaitGroup{}
+	for i, entry := range entries {
+		if resp, ok := decodeResponse(entry); ok {
+			x.deliverResponse(ctx, resp)
+			continue
+		}
+
+		req, err := decodeRequest(entry)
+		if err == nil && req.Method == protocol.MethodInitialize {
+			err = protocol.NewInvalidRequestError("initialize must not be part of a batch")
+		}
+		if err == nil {
+			err = x.session.admit(req)
+		}
+		if err != nil {
+			x.log.Errorf(ctx, "[Router][dispatchBatch] %v, message=%s\n", err, entry)
+			if req == nil || !req.IsNotification() {
+				resps[i] = (*protocol.JsonrpcPack)(protocol.NewJsonrpcResponse(requestID(req), nil, jsonrpcError(err)))
+			}
+			continue
+		}
+
+		// register all entries before handling, so a following cancellation always finds them
+		//nolint:govet
+		ctx, processing := x.startProcessing(ctx, req)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					x.log.Errorf(ctx, "[Router][handle] panic: %v, stack:\n%s\n", r, debug.Stack())
+				}
+			}()
+
+			resps[i] = x.process(ctx, req, processing)
+		}(i)
+	}
+
+	go func() {
+		wg.Wait()
+
+		// notifications have no response, nothing is written when the batch only holds notifications
+		batch := make([]*protocol.JsonrpcPack, 0, len(resps))
+		for _, resp := range resps {
+			if resp != nil {
+				batch = append(batch, resp)
+			}
+		}
+		if len(batch) > 0 {
+			x.writeBatchCH <- batch
+		}
+	}()
+}
+
+// startProcessing registers a request for cancellation and returns the context it must be handled with
+func (x *Router) startProcessing(ctx context.Context, req *protocol.JsonrpcRequest) (context.Context, *processingRequest) {
+	x.log.Debugf(ctx, "#%s. method[%s] params[%s]\n", req.GetID(), req.Method, string(req.Params))
+
+	ctx, cancel := context.WithCancel(ctx)
+	processing := &processingRequest{cancel: cancel}
+	if !req.IsNotification() {
+		x.processingReq.Store(processingKey(req.GetID()), processing)
+	}
+	return ctx, processing
+}
+
+// process handles a registered request, it returns nil for notifications and cancelled requests
+func (x *Router) process(ctx context.Context, req *protocol.JsonrpcRequest, processing *processingRequest) *protocol.JsonrpcPack {
+	defer processing.cancel()
+	if !req.IsNotification() {
+		defer x.processingReq.Delete(processingKey(req.GetID()))
+	}
+
+	// expose the request _meta, a progress reporter bound to its progress token and the client to handlers
+	meta := requestMeta(req.Params)
+	ctx = iface.NewRequestMetaContext(ctx, meta)
+	ctx = iface.NewProgressReporterContext(ctx, x.progressReporter(req, meta))
+	ctx = iface.NewClientSessionContext(ctx, clientSession{router: x})
+
+	respBs, err := x.handle(ctx, req)
+	if err != nil {
+		x.log.Errorf(ctx, "handle error: %v\n", err)
+	}
+	if req.Method == protocol.MethodInitialize {
+		x.session.initializeDone(respBs, err)
+	}
+	if req.IsNotification() {
+		return nil
+	}
+	// the client is no longer waiting for the response of a cancelled request
+	if processing.cancelled.Load() {
+		x.log.Debugf(ctx, "#%s. request cancelled, response suppressed\n", req.GetID())
+		return nil
+	}
+	if err != nil {
+		return (*protocol.JsonrpcPack)(
+			protocol.NewJsonrpcResponse(req.GetID(), nil, jsonrpcError(err)),
+		)
+	}
+	return (*protocol.JsonrpcPack)(
+		protocol.NewJsonrpcResponse(req.GetID(), respBs, nil),
+	)
+}
+
+// jsonrpcError maps an error to its JSON-RPC representation, the code and data come from the
+// protocol.Error found in its chain, otherwise it is an internal error
+func jsonrpcError(err error) *protocol.JsonrpcError {
+	var rpcErr *protocol.Error
+	if errors.As(err, &rpcErr) {
+		return &protocol.JsonrpcError{
+			Code:    rpcErr.Code,
+			Message: err.Error(),
+			Data:    rpcErr.Data,
+		}
+	}
+	code := int64(protocol.ErrorCodeInternalError)
+	if errCode, ok := err.(interface{ Code() int64 }); ok {
+		code = errCode.Code()
+	}
+	return &protocol.JsonrpcError{
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+// isBatch reports whether the message is a JSON array
+func isBatch(message json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(message, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
 func (x *Router) writeLoop(ctx context.Context, writer io.Writer) error {
 	for {
+		var bs []byte
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case pack := <-x.writePackCH:
-			bs, _ := json.Marshal(pack)
-			x.log.Debugf(ctx, "write response: %+v\n", string(bs))
-			bs = append(bs, '\n')
-			_, err := writer.Write(bs)
-			if err != nil {
-				return err
-			}
+			bs, _ = json.Marshal(pack)
+		case batch := <-x.writeBatchCH:
+			bs, _ = json.Marshal(batch)
+		}
+		x.log.Debugf(ctx, "write response: %+v\n", string(bs))
+		bs = append(bs, '\n')
+		_, err := writer.Write(bs)
+		if err != nil {
+			return err
 		}
 	}
 }
@@ -249,7 +497,7 @@ func (x *Router) handle(ctx context.Context, req *protocol.JsonrpcRequest) (resp
 		if r := recover(); r != nil {
 			msg := fmt.Sprintf("[Router][handle] panic: %v, stack:\n%s\n", r, debug.Stack())
 			x.log.Errorf(ctx, msg)
-			err = errors.New(msg)
+			err = protocol.NewInternalError("%s", msg)
 		}
 	}()
 	// handle request
@@ -260,21 +508,46 @@ func (x *Router) handle(ctx context.Context, req *protocol.JsonrpcRequest) (resp
 	return handler.Handle(ctx, req.Params)
 }
 
+// requestMeta extracts the _meta field from the request params, nil if absent
+func requestMeta(params json.RawMessage) json.RawMessage {
+	if len(params) == 0 {
+		return nil
+	}
+	var dst struct {
+		Meta json.RawMessage `json:"_meta"`
+	}
+	if err := json.Unmarshal(params, &dst); err != nil {
+		return nil
+	}
+	return dst.Meta
+}
+
+// progressReporter creates the progress reporter of a request, it does nothing for notifications or without progress token
+func (x *Router) progressReporter(req *protocol.JsonrpcRequest, meta json.RawMessage) iface.IProgressReporter {
+	var dst protocol.RequestMeta
+	if req.IsNotification() || len(meta) == 0 || json.Unmarshal(meta, &dst) != nil {
+		return iface.NewProgressReporter(nil, nil)
+	}
+	return iface.NewProgressReporter(dst.ProgressToken, x.bus.ProgressNotificationChan)
+}
+
 func (x *Router) notFoundHandleFunc(ctx context.Context, method protocol.McpMethod, message json.RawMessage) (json.RawMessage, error) {
 	x.log.Errorf(ctx, "method(%s) not found, message=%s", method, message)
-	return nil, fmt.Errorf("method(%s) not found", method)
+	return nil, protocol.NewMethodNotFoundError("method(%s) not found", method)
 }
 
 func (x *Router) cancelHandler() IHandlerFunc {
-	return func(_ context.Context, message json.RawMessage) (json.RawMessage, error) {
-		var req protocol.CancelRequest
+	return func(ctx context.Context, message json.RawMessage) (json.RawMessage, error) {
+		var req protocol.CancelledNotification
 		err := json.Unmarshal(message, &req)
 		if err != nil {
 			return nil, err
 		}
-		cancel, ok := x.processingReq.Load(string(req.ID))
+		processing, ok := x.processingReq.Load(processingKey(req.RequestID))
 		if ok {
-			cancel.(context.CancelFunc)()
+			x.log.Debugf(ctx, "#%s. cancel request, reason: %s\n", req.RequestID, req.Reason)
+			processing.(*processingRequest).cancelled.Store(true)
+			processing.(*processingRequest).cancel()
 		}
 		return nil, nil
 	}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -37,6 +39,24 @@ func (m *mockHandler) Method() protocol.McpMethod {
 	return m.method
 }
 
+// 初始化请求和初始化完成通知，会话在处理其他请求之前需要先完成初始化
+const initializeMessages = `{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"0.1.0"}}}` + "\n" +
+	`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+
+// 模拟初始化处理程序
+func newInitializeHandler(capabilities protocol.ServerCapabilities) IHandler {
+	return &mockHandler{
+		method: protocol.MethodInitialize,
+		handleFunc: func(_ context.Context, _ json.RawMessage) (json.RawMessage, error) {
+			return json.Marshal(protocol.InitializeResult{
+				ProtocolVersion: "2024-11-05",
+				Capabilities:    capabilities,
+				ServerInfo:      protocol.Implementation{Name: "test", Version: "0.1.0"},
+			})
+		},
+	}
+}
+
 // 模拟事件总线，用于测试
 type mockEventBus struct {
 	iface.EventBus
@@ -137,7 +157,7 @@ func TestNewRouter(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -147,9 +167,9 @@ func TestNewRouter(t *testing.T) {
 		t.Fatal("Router is nil")
 	}
 
-	// 验证处理程序是否正确注册
-	if len(router.handlers) != 3 {
-		t.Errorf("Expected 3 handlers, got %d", len(router.handlers))
+	// 验证处理程序是否正确注册（包括内置的取消、ping和根目录变更处理程序）
+	if len(router.handlers) != 5 {
+		t.Errorf("Expected 5 handlers, got %d", len(router.handlers))
 	}
 
 	// 验证处理程序映射是否正确
@@ -178,7 +198,7 @@ func TestRouterHandle(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -213,7 +233,7 @@ func TestRouterHandleNotFound(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -237,6 +257,27 @@ func TestRouterHandleNotFound(t *testing.T) {
 	if err.Error() != expected {
 		t.Errorf("Expected error message '%s', got '%s'", expected, err.Error())
 	}
+
+	// 验证错误代码
+	if code, _ := protocol.ErrorCode(err); code != protocol.ErrorCodeMethodNotFound {
+		t.Errorf("Expected error code %d, got %d", protocol.ErrorCodeMethodNotFound, code)
+	}
+}
+
+// 测试错误到JSON-RPC错误的映射
+func TestJsonrpcError(t *testing.T) {
+	// 包装的协议错误保留其代码和数据
+	wrapped := fmt.Errorf("call tool failed: %w", protocol.NewToolNotFoundError("echo"))
+	rpcErr := jsonrpcError(wrapped)
+	if rpcErr.Code != protocol.ErrorCodeToolNotFound || rpcErr.Message != wrapped.Error() || string(rpcErr.Data) != `{"name":"echo"}` {
+		t.Errorf("Unexpected error: %+v", rpcErr)
+	}
+
+	// 普通错误映射为内部错误
+	rpcErr = jsonrpcError(errors.New("test error"))
+	if rpcErr.Code != protocol.ErrorCodeInternalError {
+		t.Errorf("Expected error code %d, got %d", protocol.ErrorCodeInternalError, rpcErr.Code)
+	}
 }
 
 // 测试路由器处理错误
@@ -254,7 +295,7 @@ func TestRouterHandleError(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -278,6 +319,7 @@ func TestRouterHandleError(t *testing.T) {
 func TestRouterHandleWithReadWrite(t *testing.T) {
 	// 创建模拟处理程序
 	handlers := []IHandler{
+		newInitializeHandler(protocol.ServerCapabilities{}),
 		&mockHandler{
 			method: "test/method",
 			handleFunc: func(_ context.Context, _ json.RawMessage) (json.RawMessage, error) {
@@ -290,7 +332,7 @@ func TestRouterHandleWithReadWrite(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -299,6 +341,7 @@ func TestRouterHandleWithReadWrite(t *testing.T) {
 	requestJSON := `{"jsonrpc":"2.0","id":1,"method":"test/method","params":{}}`
 	preader, pwriter := io.Pipe()
 	go func() {
+		_, _ = pwriter.Write([]byte(initializeMessages))
 		_, _ = pwriter.Write([]byte(requestJSON))
 	}()
 	writer := &saveBuf{}
@@ -345,7 +388,7 @@ func TestRouterHandleEventBusNotifications(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -400,6 +443,462 @@ func TestRouterHandleEventBusNotifications(t *testing.T) {
 	}
 }
 
+// 测试请求的 _meta 通过上下文传递给处理程序
+func TestRouterRequestMeta(t *testing.T) {
+	metaCh := make(chan json.RawMessage, 1)
+	handlers := []IHandler{
+		newInitializeHandler(protocol.ServerCapabilities{}),
+		&mockHandler{
+			method: "test/meta",
+			handleFunc: func(ctx context.Context, _ json.RawMessage) (json.RawMessage, error) {
+				metaCh <- iface.RequestMetaFromContext(ctx)
+				return json.RawMessage(`{}`), nil
+			},
+		},
+	}
+
+	// 创建路由器
+	router, err := NewRouter(handlers, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, pwriter := io.Pipe()
+	go func() {
+		_ = router.Handle(ctx, preader, &saveBuf{})
+	}()
+	go func() {
+		_, _ = pwriter.Write([]byte(initializeMessages))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"test/meta","params":{"_meta":{"traceId":"abc"}}}` + "\n"))
+	}()
+
+	// 验证处理程序收到的 _meta
+	select {
+	case meta := <-metaCh:
+		if string(meta) != `{"traceId":"abc"}` {
+			t.Errorf("Expected meta to be passed to the handler, got: %s", string(meta))
+		}
+	case <-ctx.Done():
+		t.Fatal("Handler was not called")
+	}
+}
+
+// 测试取消请求后不再发送响应
+func TestRouterCancelledRequest(t *testing.T) {
+	canceledCh := make(chan struct{})
+	handlers := []IHandler{
+		newInitializeHandler(protocol.ServerCapabilities{}),
+		&mockHandler{
+			method: "test/slow",
+			handleFunc: func(ctx context.Context, _ json.RawMessage) (json.RawMessage, error) {
+				<-ctx.Done()
+				close(canceledCh)
+				return nil, ctx.Err()
+			},
+		},
+	}
+
+	// 创建路由器
+	router, err := NewRouter(handlers, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, pwriter := io.Pipe()
+	writer := &saveBuf{}
+	go func() {
+		_ = router.Handle(ctx, preader, writer)
+	}()
+	go func() {
+		_, _ = pwriter.Write([]byte(initializeMessages))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":7,"method":"test/slow","params":{}}` + "\n"))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"timeout"}}` + "\n"))
+	}()
+
+	// 验证处理程序的上下文被取消
+	select {
+	case <-canceledCh:
+	case <-ctx.Done():
+		t.Fatal("Handler context was not cancelled")
+	}
+
+	// 等待一段时间，确认没有写入响应
+	time.Sleep(100 * time.Millisecond)
+	if response := writer.String(); strings.Contains(response, `"id":7`) {
+		t.Errorf("Expected no response for cancelled request, got: %s", response)
+	}
+}
+
+// 测试以不同格式的 id 取消请求
+func TestRouterCancelledRequestIDFormat(t *testing.T) {
+	tests := []struct {
+		id       string
+		cancelID string
+	}{
+		{id: `7`, cancelID: `7.0`},
+		{id: `7`, cancelID: ` 7e0`},
+		{id: `"req-7"`, cancelID: `"req\u002d7"`},
+	}
+	for _, tt := range tests {
+		canceledCh := make(chan struct{})
+		handlers := []IHandler{
+			newInitializeHandler(protocol.ServerCapabilities{}),
+			&mockHandler{
+				method: "test/slow",
+				handleFunc: func(ctx context.Context, _ json.RawMessage) (json.RawMessage, error) {
+					<-ctx.Done()
+					close(canceledCh)
+					return nil, ctx.Err()
+				},
+			},
+		}
+		router, err := NewRouter(handlers, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+		if err != nil {
+			t.Fatalf("Failed to create router: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		preader, pwriter := io.Pipe()
+		writer := &saveBuf{}
+		go func() {
+			_ = router.Handle(ctx, preader, writer)
+		}()
+		go func() {
+			_, _ = pwriter.Write([]byte(initializeMessages))
+			_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":` + tt.id + `,"method":"test/slow","params":{}}` + "\n"))
+			_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":` + tt.cancelID + `}}` + "\n"))
+		}()
+
+		select {
+		case <-canceledCh:
+		case <-ctx.Done():
+			t.Fatalf("Request %s was not cancelled by %s", tt.id, tt.cancelID)
+		}
+		time.Sleep(50 * time.Millisecond)
+		if response := writer.String(); strings.Contains(response, `"id":`+tt.id) {
+			t.Errorf("Expected no response for cancelled request %s, got: %s", tt.id, response)
+		}
+		cancel()
+	}
+}
+
+// 测试批量请求
+func TestRouterBatch(t *testing.T) {
+	handlers := []IHandler{
+		newInitializeHandler(protocol.ServerCapabilities{}),
+		&mockHandler{
+			method: "test/echo",
+			handleFunc: func(_ context.Context, params json.RawMessage) (json.RawMessage, error) {
+				return params, nil
+			},
+		},
+	}
+
+	// 创建路由器
+	router, err := NewRouter(handlers, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, pwriter := io.Pipe()
+	writer := &saveBuf{}
+	go func() {
+		_ = router.Handle(ctx, preader, writer)
+	}()
+	go func() {
+		_, _ = pwriter.Write([]byte(initializeMessages))
+		_, _ = pwriter.Write([]byte(`[{"jsonrpc":"2.0","id":1,"method":"test/echo","params":{"n":1}},` +
+			`{"jsonrpc":"2.0","method":"test/echo","params":{"n":2}},` +
+			`{"jsonrpc":"2.0","id":3,"method":"test/echo","params":{"n":3}}]` + "\n"))
+		_, _ = pwriter.Write([]byte("[]\n"))
+	}()
+
+	// 等待批量响应和空批量的错误响应
+	var batch []protocol.JsonrpcResponse
+	var invalid *protocol.JsonrpcResponse
+	for batch == nil || invalid == nil {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Timed out waiting for batch responses, got: %s", writer.String())
+		case <-time.After(10 * time.Millisecond):
+		}
+		decoder := json.NewDecoder(strings.NewReader(writer.String()))
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				break
+			}
+			var resp protocol.JsonrpcResponse
+			switch {
+			case raw[0] == '[':
+				_ = json.Unmarshal(raw, &batch)
+			case json.Unmarshal(raw, &resp) == nil && string(resp.ID) == "null":
+				invalid = &resp
+			}
+		}
+	}
+
+	// 验证通知不在响应中
+	ids := map[string]string{}
+	for _, resp := range batch {
+		ids[string(resp.ID)] = string(resp.Result)
+	}
+	if len(ids) != 2 || ids["1"] != `{"n":1}` || ids["3"] != `{"n":3}` {
+		t.Errorf("Unexpected batch response: %+v", batch)
+	}
+
+	// 验证空批量返回无效请求错误
+	if invalid.Error == nil || invalid.Error.Code != protocol.ErrorCodeInvalidRequest {
+		t.Errorf("Expected invalid request error for empty batch, got: %+v", invalid)
+	}
+}
+
+// 测试格式错误的消息不会中断会话
+func TestRouterMalformedMessage(t *testing.T) {
+	handlers := []IHandler{
+		newInitializeHandler(protocol.ServerCapabilities{}),
+		&mockHandler{
+			method: "test/echo",
+			handleFunc: func(_ context.Context, params json.RawMessage) (json.RawMessage, error) {
+				return params, nil
+			},
+		},
+	}
+
+	// 创建路由器
+	router, err := NewRouter(handlers, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, pwriter := io.Pipe()
+	writer := &saveBuf{}
+	go func() {
+		_ = router.Handle(ctx, preader, writer)
+	}()
+	go func() {
+		_, _ = pwriter.Write([]byte(initializeMessages))
+		_, _ = pwriter.Write([]byte("{not json}\n"))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"1.0","id":1,"method":"test/echo","params":{}}` + "\n"))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"test/echo","params":{"ok":true}}` + "\n"))
+	}()
+
+	// 等待初始化响应和三个响应
+	responses := map[string]protocol.JsonrpcResponse{}
+	for len(responses) < 4 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Timed out waiting for responses, got: %s", writer.String())
+		case <-time.After(10 * time.Millisecond):
+		}
+		decoder := json.NewDecoder(strings.NewReader(writer.String()))
+		for {
+			var resp protocol.JsonrpcResponse
+			if err := decoder.Decode(&resp); err != nil {
+				break
+			}
+			responses[string(resp.ID)] = resp
+		}
+	}
+
+	// 验证解析错误和无效请求错误
+	if resp := responses["null"]; resp.Error == nil || resp.Error.Code != protocol.ErrorCodeParseError {
+		t.Errorf("Expected parse error, got: %+v", resp)
+	}
+	if resp := responses["1"]; resp.Error == nil || resp.Error.Code != protocol.ErrorCodeInvalidRequest {
+		t.Errorf("Expected invalid request error, got: %+v", resp)
+	}
+
+	// 验证会话继续处理后续请求
+	if resp := responses["2"]; resp.Error != nil || string(resp.Result) != `{"ok":true}` {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+// 测试初始化生命周期
+func TestRouterLifecycle(t *testing.T) {
+	handlers := []IHandler{
+		newInitializeHandler(protocol.ServerCapabilities{Tools: &protocol.ServerTools{}}),
+		&mockHandler{method: protocol.MethodListTools},
+		&mockHandler{method: protocol.MethodListPrompts},
+	}
+
+	// 创建路由器
+	router, err := NewRouter(handlers, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, pwriter := io.Pipe()
+	writer := &saveBuf{}
+	go func() {
+		_ = router.Handle(ctx, preader, writer)
+	}()
+	go func() {
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}` + "\n"))
+		_, _ = pwriter.Write([]byte(initializeMessages))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{}}` + "\n"))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":3,"method":"prompts/list","params":{}}` + "\n"))
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":4,"method":"tools/list","params":{}}` + "\n"))
+	}()
+
+	// 等待所有响应
+	responses := map[string]protocol.JsonrpcResponse{}
+	for len(responses) < 5 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Timed out waiting for responses, got: %s", writer.String())
+		case <-time.After(10 * time.Millisecond):
+		}
+		decoder := json.NewDecoder(strings.NewReader(writer.String()))
+		for {
+			var resp protocol.JsonrpcResponse
+			if err := decoder.Decode(&resp); err != nil {
+				break
+			}
+			responses[string(resp.ID)] = resp
+		}
+	}
+
+	expected := map[string]int64{
+		"1": protocol.ErrorCodeServerNotInitialized, // 初始化之前的请求
+		"2": protocol.ErrorCodeInvalidRequest,       // 重复的初始化
+		"3": protocol.ErrorCodeMethodNotFound,       // 未协商的能力
+	}
+	for id, code := range expected {
+		if resp := responses[id]; resp.Error == nil || resp.Error.Code != code {
+			t.Errorf("Expected error code %d for request %s, got: %+v", code, id, resp)
+		}
+	}
+	for _, id := range []string{`"init"`, "4"} {
+		if resp := responses[id]; resp.Error != nil || resp.Result == nil {
+			t.Errorf("Expected result for request %s, got: %+v", id, resp)
+		}
+	}
+}
+
+// 测试ping请求，初始化之前也允许
+func TestRouterPing(t *testing.T) {
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, pwriter := io.Pipe()
+	writer := &saveBuf{}
+	go func() {
+		_ = router.Handle(ctx, preader, writer)
+	}()
+	go func() {
+		_, _ = pwriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+	}()
+
+	for !strings.Contains(writer.String(), `"id":1`) {
+		select {
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for ping response")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if response := writer.String(); !strings.Contains(response, `"result":{}`) {
+		t.Errorf("Expected empty result, got: %s", response)
+	}
+}
+
+// 测试客户端不响应ping时会话结束
+func TestRouterKeepaliveTimeout(t *testing.T) {
+	timeoutCh := make(chan struct{})
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{
+		KeepaliveInterval:  20 * time.Millisecond,
+		KeepaliveMaxMissed: 2,
+		OnKeepaliveTimeout: func(_ context.Context, _ error) {
+			close(timeoutCh)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, _ := io.Pipe()
+	writer := &saveBuf{}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- router.Handle(ctx, preader, writer)
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrKeepaliveTimeout) {
+			t.Errorf("Expected keepalive timeout, got: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Session was not torn down")
+	}
+	select {
+	case <-timeoutCh:
+	default:
+		t.Error("Keepalive timeout callback was not called")
+	}
+	if !strings.Contains(writer.String(), `"method":"ping"`) {
+		t.Errorf("Expected ping requests, got: %s", writer.String())
+	}
+}
+
+// 测试客户端响应ping时会话保持
+func TestRouterKeepaliveAnswered(t *testing.T) {
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{
+		KeepaliveInterval:  20 * time.Millisecond,
+		KeepaliveMaxMissed: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// 模拟客户端，响应每个ping请求
+	clientReader, routerWriter := io.Pipe()
+	routerReader, clientWriter := io.Pipe()
+	go func() {
+		decoder := json.NewDecoder(clientReader)
+		for {
+			var req protocol.JsonrpcRequest
+			if err := decoder.Decode(&req); err != nil {
+				return
+			}
+			bs, _ := json.Marshal(protocol.NewJsonrpcResponse(req.ID, json.RawMessage("{}"), nil))
+			_, _ = clientWriter.Write(append(bs, '\n'))
+		}
+	}()
+
+	err = router.Handle(ctx, routerReader, routerWriter)
+	if errors.Is(err, ErrKeepaliveTimeout) {
+		t.Errorf("Unexpected keepalive timeout")
+	}
+}
+
 // 测试NewIRouter函数
 func TestNewIRouter(t *testing.T) {
 	// 创建模拟处理程序和事件总线
@@ -407,7 +906,7 @@ func TestNewIRouter(t *testing.T) {
 	bus := newMockEventBus()
 
 	// 创建路由器
-	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog)
+	router, err := NewRouter(handlers, bus.EventBus, logger.DefaultLog, Options{})
 	if err != nil {
 		t.Fatalf("Failed to create router: %v", err)
 	}
@@ -442,3 +941,218 @@ func (x *saveBuf) String() string {
 	defer x.mu.Unlock()
 	return x.buf.String()
 }
+
+// 测试发往客户端的请求超时后取消
+func TestRouterRequestTimeout(t *testing.T) {
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{
+		RequestTimeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	preader, _ := io.Pipe()
+	writer := &saveBuf{}
+	go func() {
+		_ = router.Handle(ctx, preader, writer)
+	}()
+
+	err = router.request(ctx, protocol.MethodCreateMessage, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected request timeout, got: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("Request was not bounded by the request timeout")
+	}
+
+	// 等待取消通知写出
+	for !strings.Contains(writer.String(), protocol.NotificationCancelled) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Expected a cancelled notification, got: %s", writer.String())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// 测试根目录按会话缓存，并在客户端通知变更后失效
+func TestRouterRootsCache(t *testing.T) {
+	changedCh := make(chan struct{}, 1)
+	router, err := NewRouter([]IHandler{newInitializeHandler(protocol.ServerCapabilities{})},
+		newMockEventBus().EventBus, logger.DefaultLog, Options{
+			OnRootsListChanged: func(_ context.Context) {
+				changedCh <- struct{}{}
+			},
+		})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// 模拟客户端，每次响应roots/list时返回新的根目录
+	clientReader, routerWriter := io.Pipe()
+	routerReader, clientWriter := io.Pipe()
+	var listed atomic.Int32
+	go func() {
+		decoder := json.NewDecoder(clientReader)
+		for {
+			var req protocol.JsonrpcRequest
+			if err := decoder.Decode(&req); err != nil {
+				return
+			}
+			if req.Method != protocol.MethodListRoots {
+				continue
+			}
+			n := listed.Add(1)
+			result, _ := json.Marshal(protocol.ListRootsResult{
+				Roots: []protocol.Root{{URI: fmt.Sprintf("file:///workspace%d", n)}},
+			})
+			bs, _ := json.Marshal(protocol.NewJsonrpcResponse(req.ID, result, nil))
+			_, _ = clientWriter.Write(append(bs, '\n'))
+		}
+	}()
+	go func() {
+		_ = router.Handle(ctx, routerReader, routerWriter)
+	}()
+	_, _ = clientWriter.Write([]byte(initializeMessages))
+
+	for i := 0; i < 2; i++ {
+		roots, err := router.listRoots(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list roots: %v", err)
+		}
+		if len(roots) != 1 || roots[0].URI != "file:///workspace1" {
+			t.Errorf("Unexpected roots: %+v", roots)
+		}
+	}
+	if listed.Load() != 1 {
+		t.Errorf("Expected roots to be requested once, got %d", listed.Load())
+	}
+
+	_, _ = clientWriter.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}` + "\n"))
+	select {
+	case <-changedCh:
+	case <-ctx.Done():
+		t.Fatal("Roots list changed callback was not called")
+	}
+
+	roots, err := router.listRoots(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list roots: %v", err)
+	}
+	if len(roots) != 1 || roots[0].URI != "file:///workspace2" {
+		t.Errorf("Expected roots to be requested again, got %+v", roots)
+	}
+}
+
+// 测试请求超时不作用于等待用户回答的 elicitation
+func TestRouterRequestTimeoutElicit(t *testing.T) {
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{
+		RequestTimeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	if timeout := router.requestTimeout(protocol.MethodElicit); timeout != 0 {
+		t.Errorf("Expected elicitation to be unbounded, got: %s", timeout)
+	}
+	if timeout := router.requestTimeout(protocol.MethodCreateMessage); timeout != 20*time.Millisecond {
+		t.Errorf("Unexpected sampling timeout: %s", timeout)
+	}
+
+	router, err = NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	if timeout := router.requestTimeout(protocol.MethodCreateMessage); timeout != 0 {
+		t.Errorf("Expected no request timeout by default, got: %s", timeout)
+	}
+}
+
+// 测试 jsonrpc 版本非法的响应使等待中的请求失败
+func TestRouterInvalidResponseVersion(t *testing.T) {
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// 模拟客户端，依次以单个响应和批量响应回复
+	clientReader, routerWriter := io.Pipe()
+	routerReader, clientWriter := io.Pipe()
+	go func() {
+		decoder := json.NewDecoder(clientReader)
+		for batch := false; ; batch = !batch {
+			var req protocol.JsonrpcRequest
+			if err := decoder.Decode(&req); err != nil {
+				return
+			}
+			resp := `{"jsonrpc":"1.0","id":` + string(req.ID) + `,"result":{}}`
+			if batch {
+				resp = "[" + resp + "]"
+			}
+			_, _ = clientWriter.Write([]byte(resp + "\n"))
+		}
+	}()
+	go func() {
+		_ = router.Handle(ctx, routerReader, routerWriter)
+	}()
+
+	for _, path := range []string{"single", "batch"} {
+		err := router.request(ctx, protocol.MethodPing, nil, nil)
+		var clientErr *iface.ClientRequestError
+		if !errors.As(err, &clientErr) || clientErr.Err.Code != protocol.ErrorCodeInvalidRequest {
+			t.Errorf("Expected an invalid request error for the %s response, got: %v", path, err)
+		}
+	}
+}
+
+// 测试以不同格式回显 id 的响应仍能匹配到请求
+func TestRouterResponseIDFormat(t *testing.T) {
+	router, err := NewRouter(nil, newMockEventBus().EventBus, logger.DefaultLog, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// 模拟客户端，依次以 N.0 与 "N" 回显请求 id
+	clientReader, routerWriter := io.Pipe()
+	routerReader, clientWriter := io.Pipe()
+	go func() {
+		decoder := json.NewDecoder(clientReader)
+		for quoted := false; ; quoted = !quoted {
+			var req protocol.JsonrpcRequest
+			if err := decoder.Decode(&req); err != nil {
+				return
+			}
+			id := " " + string(req.ID) + ".0"
+			if quoted {
+				id = `"` + string(req.ID) + `"`
+			}
+			_, _ = clientWriter.Write([]byte(`{"jsonrpc":"2.0","id":` + id + `,"result":{}}` + "\n"))
+		}
+	}()
+	go func() {
+		_ = router.Handle(ctx, routerReader, routerWriter)
+	}()
+
+	if err := router.request(ctx, protocol.MethodPing, nil, nil); err != nil {
+		t.Errorf("Expected the response to match, got: %v", err)
+	}
+
+	// 数字 id 回显为字符串时请求失败，而不是一直等待
+	err = router.request(ctx, protocol.MethodPing, nil, nil)
+	var clientErr *iface.ClientRequestError
+	if !errors.As(err, &clientErr) || clientErr.Err.Code != protocol.ErrorCodeInvalidRequest {
+		t.Errorf("Expected an invalid request error, got: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package router
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/mcp4go/mcp4go/protocol"
+)
+
+// sessionState is a step of the connection lifecycle
+type sessionState int32
+
+const (
+	// stateAwaitingInitialize accepts only initialize and ping
+	stateAwaitingInitialize sessionState = iota
+	// stateInitializing is entered on initialize, requests are accepted once it has been answered
+	stateInitializing
+	// stateReady is entered on notifications/initialized
+	stateReady
+	// stateShuttingDown is entered when the connection is closing
+	stateShuttingDown
+)
+
+func (x sessionState) String() string {
+	switch x {
+	case stateAwaitingInitialize:
+		return "awaiting initialize"
+	case stateInitializing:
+		return "initializing"
+	case stateReady:
+		return "ready"
+	case stateShuttingDown:
+		return "shutting down"
+	default:
+		return "unknown"
+	}
+}
+
+// session tracks the lifecycle and the negotiated capabilities of a connection
+type session struct {
+	mu                 sync.RWMutex
+	state              sessionState
+	initialized        bool
+	clientInfo         protocol.Implementation
+	clientCapabilities protocol.ClientCapabilities
+	serverCapabilities protocol.ServerCapabilities
+}
+
+// admit checks that a message is allowed in the current state and applies the transitions it triggers,
+// it must be called in the order the messages are received
+func (x *session) admit(req *protocol.JsonrpcRequest) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	switch req.Method {
+	case protocol.MethodPing, protocol.NotificationCancelled:
+		return nil
+	case protocol.MethodInitialize:
+		if x.state != stateAwaitingInitialize {
+			return protocol.NewInvalidRequestError("initialize is not allowed in state %s", x.state)
+		}
+		// a malformed request is reported by the initialize handler and rolled back by initializeDone
+		var params protocol.InitializeRequest
+		_ = json.Unmarshal(req.Params, &params)
+		x.clientInfo = params.ClientInfo
+		x.clientCapabilities = params.Capabilities
+		x.state = stateInitializing
+		return nil
+	case protocol.NotificationInitialized:
+		if x.state != stateInitializing || !x.initialized {
+			return protocol.NewInvalidRequestError("%s is not allowed in state %s", req.Method, x.state)
+		}
+		x.state = stateReady
+		return nil
+	}
+
+	switch {
+	case x.state == stateShuttingDown:
+		return protocol.NewInvalidRequestError("session is shutting down")
+	case !x.initialized:
+		return protocol.NewServerNotInitializedError("method(%s) is not allowed before initialization", req.Method)
+	}
+	return x.checkCapability(req.Method)
+}
+
+// checkCapability rejects methods of capabilities the server did not advertise
+func (x *session) checkCapability(method protocol.McpMethod) error {
+	caps := x.serverCapabilities
+	supported := true
+	switch method {
+	case protocol.MethodListTools, protocol.MethodCallTool:
+		supported = caps.Tools != nil
+	case protocol.MethodListPrompts, protocol.MethodGetPrompt:
+		supported = caps.Prompts != nil
+	case protocol.MethodListResources, protocol.MethodReadResource, protocol.MethodListResourceTemplates:
+		supported = caps.Resources != nil
+	case protocol.MethodSubscribe, protocol.MethodUnsubscribe:
+		supported = caps.Resources != nil && caps.Resources.Subscribe
+	case protocol.MethodSetLevel:
+		supported = caps.Logging != nil
+	case protocol.MethodComplete:
+		supported = caps.Completions != nil
+	}
+	if !supported {
+		return protocol.NewMethodNotFoundError("method(%s) not found: capability not negotiated", method)
+	}
+	return nil
+}
+
+// initializeDone records the outcome of the initialize request, a failure allows the client to try again
+func (x *session) initializeDone(result json.RawMessage, err error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	var res protocol.InitializeResult
+	if err == nil {
+		err = json.Unmarshal(result, &res)
+	}
+	if err != nil {
+		x.state = stateAwaitingInitialize
+		x.clientInfo = protocol.Implementation{}
+		x.clientCapabilities = protocol.ClientCapabilities{}
+		return
+	}
+	x.serverCapabilities = res.Capabilities
+	x.initialized = true
+}
+
+// client returns the information and capabilities declared by the client in initialize
+func (x *session) client() (protocol.Implementation, protocol.ClientCapabilities) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.clientInfo, x.clientCapabilities
+}
+
+// shutdown rejects every following request
+func (x *session) shutdown() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.state = stateShuttingDown
+}
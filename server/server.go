@@ -9,6 +9,7 @@ import (
 	"github.com/mcp4go/mcp4go/protocol"
 	"github.com/mcp4go/mcp4go/server/iface"
 	"github.com/mcp4go/mcp4go/server/internal/handlers"
+	"github.com/mcp4go/mcp4go/server/internal/router"
 	"github.com/mcp4go/mcp4go/server/transport"
 )
 
@@ -23,8 +24,19 @@ type options struct {
 	resourceBuilder iface.IResourceBuilder
 	promptBuilder   iface.IPromptBuilder
 	toolBuilder     iface.IToolBuilder
+	// completionBuilder is optional, completions are advertised only when it is set
+	completionBuilder iface.ICompletionBuilder
+
+	// methodHandlers serve custom methods or override built-in ones
+	methodHandlers []IHandler
+	experimental   map[string]interface{}
+
+	routerOptions router.Options
 }
 
+// ErrKeepaliveTimeout ends a session whose client missed too many pings, see WithKeepalive
+var ErrKeepaliveTimeout = router.ErrKeepaliveTimeout
+
 type Server struct {
 	options   options
 	log       *logger.LogHelper
@@ -48,6 +60,18 @@ func NewServer(transport transport.ITransport, opts ...Option) (*Server, func(),
 
 func (x *Server) Run(ctx context.Context) error {
 	return x.transport.Run(ctx, func(ctx context.Context, reader io.Reader, writer io.Writer) error {
+		completion := iface.ICompletion(&dummyICompletion{})
+		var completions *protocol.ServerCompletions
+		if x.options.completionBuilder != nil {
+			completion = x.options.completionBuilder.Build()
+			completions = &protocol.ServerCompletions{}
+		}
+
+		experimental, err := x.experimentalCapabilities()
+		if err != nil {
+			return err
+		}
+
 		router, err := initRouter(
 			x.options.logger,
 			handlers.NewInitializeHandler(
@@ -62,6 +86,9 @@ func (x *Server) Run(ctx context.Context) error {
 					Tools: &protocol.ServerTools{
 						ListChanged: x.options.toolBuilder.ListChanged(),
 					},
+					Logging:      &protocol.ServerLogging{},
+					Completions:  completions,
+					Experimental: experimental,
 				},
 				x.options.serverInfo,
 				x.options.instructions,
@@ -71,8 +98,11 @@ func (x *Server) Run(ctx context.Context) error {
 			x.options.resourceBuilder.Build(),
 			x.options.promptBuilder.Build(),
 			x.options.toolBuilder.Build(),
+			completion,
 			iface.NewEventBus(),
 			x.options.requestDecodeFn,
+			x.options.methodHandlers,
+			x.options.routerOptions,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to initialize router: %w", err)
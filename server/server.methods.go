@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcp4go/mcp4go/protocol"
+	"github.com/mcp4go/mcp4go/server/internal/router"
+)
+
+// IHandler serves the requests or notifications of a method, see WithMethodHandler
+type IHandler = router.IHandler
+
+// HandleMethod registers a typed handler for a custom request method, the params are decoded into Req
+// and the returned value is marshalled as the result
+func HandleMethod[Req any, Resp any](method protocol.McpMethod, fn func(ctx context.Context, req Req) (Resp, error)) OptionFunc {
+	return WithMethodHandler(router.NewIHandlerFuncWrapper(func(ctx context.Context, message json.RawMessage) (json.RawMessage, error) {
+		var req Req
+		if err := decodeParams(message, &req); err != nil {
+			return nil, err
+		}
+		resp, err := fn(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	}, method))
+}
+
+// HandleNotification registers a typed handler for a custom notification, the params are decoded into Req
+func HandleNotification[Req any](method protocol.McpMethod, fn func(ctx context.Context, req Req) error) OptionFunc {
+	return WithMethodHandler(router.NewIHandlerFuncWrapper(func(ctx context.Context, message json.RawMessage) (json.RawMessage, error) {
+		var req Req
+		if err := decodeParams(message, &req); err != nil {
+			return nil, err
+		}
+		return nil, fn(ctx, req)
+	}, method))
+}
+
+func decodeParams(message json.RawMessage, v interface{}) error {
+	if len(message) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(message, v); err != nil {
+		return protocol.NewInvalidParamsError("invalid params: %w", err)
+	}
+	return nil
+}
+
+// builtinMethods are served by the server itself, overriding them does not advertise a capability
+var builtinMethods = map[protocol.McpMethod]bool{
+	protocol.MethodInitialize:             true,
+	protocol.MethodPing:                   true,
+	protocol.MethodComplete:               true,
+	protocol.MethodListTools:              true,
+	protocol.MethodCallTool:               true,
+	protocol.MethodListResources:          true,
+	protocol.MethodReadResource:           true,
+	protocol.MethodSubscribe:              true,
+	protocol.MethodUnsubscribe:            true,
+	protocol.MethodListResourceTemplates:  true,
+	protocol.MethodListPrompts:            true,
+	protocol.MethodGetPrompt:              true,
+	protocol.MethodSetLevel:               true,
+	protocol.NotificationInitialized:      true,
+	protocol.NotificationCancelled:        true,
+	protocol.NotificationRootsListChanged: true,
+}
+
+// experimentalCapabilities advertises the custom methods and the capabilities set with WithExperimentalCapability
+func (x *Server) experimentalCapabilities() (json.RawMessage, error) {
+	experimental := make(map[string]interface{})
+	for _, handler := range x.options.methodHandlers {
+		if !builtinMethods[handler.Method()] {
+			experimental[string(handler.Method())] = struct{}{}
+		}
+	}
+	for name, value := range x.options.experimental {
+		experimental[name] = value
+	}
+	if len(experimental) == 0 {
+		return nil, nil
+	}
+	bs, err := json.Marshal(experimental)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal experimental capabilities: %w", err)
+	}
+	return bs, nil
+}
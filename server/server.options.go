@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/mcp4go/mcp4go/pkg/logger"
 	"github.com/mcp4go/mcp4go/protocol"
@@ -81,6 +82,13 @@ func WithToolBuilder(tool iface.IToolBuilder) OptionFunc {
 	}
 }
 
+// WithCompletionBuilder sets the completion builder interface and advertises the completions capability
+func WithCompletionBuilder(completion iface.ICompletionBuilder) OptionFunc {
+	return func(o *options) {
+		o.completionBuilder = completion
+	}
+}
+
 // WithRequestDecodeFunc sets the request decode function
 func WithRequestDecodeFunc(fn handlers.RequestDecodeFunc) OptionFunc {
 	return func(o *options) {
@@ -88,6 +96,50 @@ func WithRequestDecodeFunc(fn handlers.RequestDecodeFunc) OptionFunc {
 	}
 }
 
+// WithKeepalive pings every client at the given interval and tears down its session after maxMissed
+// consecutive unanswered pings, onTimeout is called with the session context and ErrKeepaliveTimeout when that happens
+func WithKeepalive(interval time.Duration, maxMissed int, onTimeout func(ctx context.Context, err error)) OptionFunc {
+	return func(o *options) {
+		o.routerOptions.KeepaliveInterval = interval
+		o.routerOptions.KeepaliveMaxMissed = maxMissed
+		o.routerOptions.OnKeepaliveTimeout = onTimeout
+	}
+}
+
+// WithRequestTimeout bounds the time spent waiting for the client to answer a request sent by the server,
+// such as sampling or listing roots, there is none by default. Elicitation waits for the user and is not bounded
+func WithRequestTimeout(timeout time.Duration) OptionFunc {
+	return func(o *options) {
+		o.routerOptions.RequestTimeout = timeout
+	}
+}
+
+// WithRootsListChangedHandler sets a callback invoked when a client notifies its roots changed,
+// iface.ListRoots called with its context fetches the new roots
+func WithRootsListChangedHandler(handler func(ctx context.Context)) OptionFunc {
+	return func(o *options) {
+		o.routerOptions.OnRootsListChanged = handler
+	}
+}
+
+// WithMethodHandler registers handlers for custom methods or notifications, a handler replaces the built-in
+// one of the same method. Custom methods are advertised under the experimental capabilities
+func WithMethodHandler(handlers ...IHandler) OptionFunc {
+	return func(o *options) {
+		o.methodHandlers = append(o.methodHandlers, handlers...)
+	}
+}
+
+// WithExperimentalCapability advertises a non-standard capability, value is marshalled as JSON
+func WithExperimentalCapability(name string, value interface{}) OptionFunc {
+	return func(o *options) {
+		if o.experimental == nil {
+			o.experimental = make(map[string]interface{})
+		}
+		o.experimental[name] = value
+	}
+}
+
 type dummyIResourceBuilder struct{}
 
 func (x *dummyIResourceBuilder) Build() iface.IResource {
@@ -175,3 +227,9 @@ func (x *dummyITool) Call(_ context.Context, _ string, _ json.RawMessage) ([]pro
 func (x *dummyITool) StartWatchListChanged(_ context.Context, _ string, _ chan<- protocol.ToolListChangedNotification) error {
 	return nil
 }
+
+type dummyICompletion struct{}
+
+func (x *dummyICompletion) Complete(_ context.Context, _ protocol.CompletionReference, _ protocol.CompletionArgument, _ map[string]string) (protocol.Completion, error) {
+	return protocol.Completion{}, nil
+}
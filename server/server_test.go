@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -444,3 +445,244 @@ type testLogger struct {
 func (l *testLogger) Logf(_ context.Context, level logger.Level, message string, args ...interface{}) {
 	l.messages.WriteString(fmt.Sprintf("[%s] %s\n", level, fmt.Sprintf(message, args...)))
 }
+
+// 测试自定义方法的注册、覆盖与实验性能力声明
+func TestServerCustomMethods(t *testing.T) {
+	type echoRequest struct {
+		Text string `json:"text"`
+	}
+	type echoResult struct {
+		Text   string `json:"text"`
+		Shared bool   `json:"shared"`
+	}
+
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+	mockTransp := newMockTransport()
+	mockTransp.SetReaderWriter(serverReader, serverWriter)
+
+	server, cleanup, err := NewServer(mockTransp,
+		HandleMethod("vendor/echo", func(ctx context.Context, req echoRequest) (echoResult, error) {
+			var shared struct {
+				Enabled bool `json:"enabled"`
+			}
+			_, err := iface.ClientExperimentalCapability(ctx, "vendor/share", &shared)
+			return echoResult{Text: req.Text, Shared: shared.Enabled}, err
+		}),
+		HandleMethod(protocol.MethodPing, func(_ context.Context, _ struct{}) (map[string]string, error) {
+			return map[string]string{"pong": "custom"}, nil
+		}),
+		WithExperimentalCapability("vendor/feature", map[string]int{"version": 2}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() {
+		_ = server.Run(ctx)
+	}()
+
+	go func() {
+		_, _ = clientWriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{"experimental":{"vendor/share":{"enabled":true}}},"clientInfo":{"name":"test","version":"0.1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"vendor/echo","params":{"text":"hi"}}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"ping"}` + "\n"))
+	}()
+
+	responses := make(map[string]json.RawMessage)
+	decoder := json.NewDecoder(clientReader)
+	for len(responses) < 3 {
+		var resp protocol.JsonrpcResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		if resp.Error != nil {
+			t.Fatalf("Unexpected error response: %+v", resp.Error)
+		}
+		responses[string(resp.ID)] = resp.Result
+	}
+
+	// 验证自定义方法声明在实验性能力中，内置方法不声明
+	var initResult protocol.InitializeResult
+	if err := json.Unmarshal(responses["1"], &initResult); err != nil {
+		t.Fatal(err)
+	}
+	var experimental map[string]json.RawMessage
+	if err := json.Unmarshal(initResult.Capabilities.Experimental, &experimental); err != nil {
+		t.Fatalf("Invalid experimental capabilities: %s", initResult.Capabilities.Experimental)
+	}
+	if _, ok := experimental["vendor/echo"]; !ok || string(experimental["vendor/feature"]) != `{"version":2}` {
+		t.Errorf("Unexpected experimental capabilities: %s", initResult.Capabilities.Experimental)
+	}
+	if _, ok := experimental["ping"]; ok {
+		t.Error("Built-in method should not be advertised")
+	}
+
+	if string(responses["2"]) != `{"text":"hi","shared":true}` {
+		t.Errorf("Unexpected custom method result: %s", responses["2"])
+	}
+	if string(responses["3"]) != `{"pong":"custom"}` {
+		t.Errorf("Expected the built-in ping to be overridden, got: %s", responses["3"])
+	}
+}
+
+// 通过管道运行服务器并完成初始化，返回向服务器写入消息和读取消息的函数
+func runPipeServer(t *testing.T, capabilities string, opts ...Option) (func(msg string), func() protocol.JsonrpcResponse) {
+	t.Helper()
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+	mockTransp := newMockTransport()
+	mockTransp.SetReaderWriter(serverReader, serverWriter)
+
+	server, cleanup, err := NewServer(mockTransp, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(func() {
+		cancel()
+		_ = clientWriter.Close()
+		_ = clientReader.Close()
+		cleanup()
+	})
+	go func() {
+		_ = server.Run(ctx)
+	}()
+
+	write := func(msg string) {
+		go func() {
+			_, _ = clientWriter.Write([]byte(msg + "\n"))
+		}()
+	}
+	decoder := json.NewDecoder(clientReader)
+	read := func() protocol.JsonrpcResponse {
+		t.Helper()
+		var resp protocol.JsonrpcResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		return resp
+	}
+
+	write(`{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":` + capabilities + `,"clientInfo":{"name":"test","version":"0.1.0"}}}`)
+	if resp := read(); resp.Error != nil {
+		t.Fatalf("Initialize failed: %+v", resp.Error)
+	}
+	write(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	return write, read
+}
+
+// 测试工具调用失败时协议错误与执行错误的区分
+func TestServerCallToolErrors(t *testing.T) {
+	type failArgs struct {
+		Reason string `json:"reason"`
+	}
+	tool := iface.NewFunctionalToolWrapper("fail", "always fails",
+		func(_ context.Context, args failArgs) ([]protocol.Content, error) {
+			return nil, fmt.Errorf("backend: %w", protocol.NewInternalError("%s", args.Reason))
+		},
+	)
+	validate := iface.NewFunctionalToolWrapper("validate", "rejects its input",
+		func(_ context.Context, args failArgs) ([]protocol.Content, error) {
+			return nil, protocol.NewInvalidParamsError("invalid reason %q", args.Reason)
+		},
+	)
+	write, read := runPipeServer(t, `{}`, WithToolBuilder(iface.NewFunctionalToolsBuilder(tool, validate)))
+
+	// 工具自身返回的协议错误属于执行失败，在结果中报告
+	write(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fail","arguments":{"reason":"down"}}}`)
+	resp := read()
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error response: %+v", resp.Error)
+	}
+	var result protocol.CallToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || len(result.Content) != 1 || result.Content[0].Text != "backend: down" {
+		t.Errorf("Expected an isError result, got: %s", resp.Result)
+	}
+
+	// 工具运行后返回的非法参数错误同样在结果中报告
+	write(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"validate","arguments":{"reason":"none"}}}`)
+	resp = read()
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error response: %+v", resp.Error)
+	}
+	result = protocol.CallToolResult{}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || len(result.Content) != 1 || result.Content[0].Text != `invalid reason "none"` {
+		t.Errorf("Expected an isError result, got: %s", resp.Result)
+	}
+
+	// 拒绝调用的未知工具与非法参数是协议错误
+	write(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"missing","arguments":{}}}`)
+	if resp := read(); resp.Error == nil || resp.Error.Code != protocol.ErrorCodeToolNotFound {
+		t.Errorf("Expected a tool not found error, got: %+v", resp)
+	}
+	write(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fail","arguments":{"reason":1}}}`)
+	if resp := read(); resp.Error == nil || resp.Error.Code != protocol.ErrorCodeInvalidParams {
+		t.Errorf("Expected an invalid params error, got: %+v", resp)
+	}
+}
+
+// 测试客户端拒绝工具发起的 elicitation 请求时，工具调用以 isError 结果结束
+func TestServerCallToolClientRequestError(t *testing.T) {
+	type askArgs struct{}
+	type answer struct {
+		Name string `json:"name"`
+	}
+	clientErrCh := make(chan error, 1)
+	tool := iface.NewFunctionalToolWrapper("ask", "asks the user",
+		func(ctx context.Context, _ askArgs) ([]protocol.Content, error) {
+			_, err := iface.Elicit[answer](ctx, "your name?")
+			clientErrCh <- err
+			return nil, err
+		},
+	)
+	write, read := runPipeServer(t, `{"elicitation":{}}`, WithToolBuilder(iface.NewFunctionalToolsBuilder(tool)))
+
+	write(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"ask","arguments":{}}}`)
+	req := read()
+	if req.Method != protocol.MethodElicit {
+		t.Fatalf("Expected an elicitation request, got: %+v", req)
+	}
+	write(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32601,"message":"method not found"}}`)
+
+	resp := read()
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error response: %+v", resp.Error)
+	}
+	var result protocol.CallToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError {
+		t.Errorf("Expected an isError result, got: %s", resp.Result)
+	}
+
+	var clientErr *iface.ClientRequestError
+	if err := <-clientErrCh; !errors.As(err, &clientErr) || clientErr.Method != protocol.MethodElicit ||
+		clientErr.Err.Code != protocol.ErrorCodeMethodNotFound {
+		t.Errorf("Expected a client request error, got: %v", err)
+	}
+}
+
+// 测试服务器声明 logging 能力，logging/setLevel 不被能力检查拒绝
+func TestServerSetLevel(t *testing.T) {
+	write, read := runPipeServer(t, `{}`)
+
+	write(`{"jsonrpc":"2.0","id":1,"method":"logging/setLevel","params":{"level":"warning"}}`)
+	if resp := read(); resp.Error != nil {
+		t.Fatalf("Unexpected error response: %+v", resp.Error)
+	}
+	write(`{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"loud"}}`)
+	if resp := read(); resp.Error == nil || resp.Error.Code != protocol.ErrorCodeInvalidParams {
+		t.Errorf("Expected an invalid params error, got: %+v", resp)
+	}
+}
@@ -11,6 +11,6 @@ import (
 )
 
 func initRouter(logger.ILogger, *handlers.InitializeHandler, *handlers.SetLevelHandler, iface.IResource,
-	iface.IPrompt, iface.ITool, iface.EventBus, handlers.RequestDecodeFunc) (router.IRouter, error) {
+	iface.IPrompt, iface.ITool, iface.ICompletion, iface.EventBus, handlers.RequestDecodeFunc, handlers.ExtraHandlers, router.Options) (router.IRouter, error) {
 	panic(wire.Build(router.ProviderSet, handlers.Provider))
 }
@@ -15,7 +15,7 @@ import (
 
 // Injectors from wire.go:
 
-func initRouter(iLogger logger.ILogger, initializeHandler *handlers.InitializeHandler, setLevelHandler *handlers.SetLevelHandler, iResource iface.IResource, iPrompt iface.IPrompt, iTool iface.ITool, eventBus iface.EventBus, requestDecodeFunc handlers.RequestDecodeFunc) (router.IRouter, error) {
+func initRouter(iLogger logger.ILogger, initializeHandler *handlers.InitializeHandler, setLevelHandler *handlers.SetLevelHandler, iResource iface.IResource, iPrompt iface.IPrompt, iTool iface.ITool, iCompletion iface.ICompletion, eventBus iface.EventBus, requestDecodeFunc handlers.RequestDecodeFunc, extraHandlers handlers.ExtraHandlers, routerOptions router.Options) (router.IRouter, error) {
 	initializedHandler := handlers.NewInitializedHandler()
 	listPromptsHandler := handlers.NewListPromptsHandler(iPrompt, requestDecodeFunc)
 	getPromptHandler := handlers.NewGetPromptHandler(iPrompt, requestDecodeFunc)
@@ -26,8 +26,10 @@ func initRouter(iLogger logger.ILogger, initializeHandler *handlers.InitializeHa
 	unsubscribeHandler := handlers.NewUnsubscribeHandler(iResource, requestDecodeFunc)
 	listToolsHandler := handlers.NewListToolsHandler(iTool, requestDecodeFunc)
 	callToolHandler := handlers.NewCallToolHandler(iTool, requestDecodeFunc)
-	v := handlers.NewIHandlers(initializeHandler, initializedHandler, setLevelHandler, listPromptsHandler, getPromptHandler, listResourcesHandler, readResourceHandler, listResourceTemplatesHandler, subscribeHandler, unsubscribeHandler, listToolsHandler, callToolHandler)
-	routerRouter, err := router.NewRouter(v, eventBus, iLogger)
+	completeHandler := handlers.NewCompleteHandler(iCompletion, requestDecodeFunc)
+	loggingMessageSender := handlers.NewLoggingMessageSender(eventBus, setLevelHandler)
+	v := handlers.NewIHandlers(initializeHandler, initializedHandler, setLevelHandler, listPromptsHandler, getPromptHandler, listResourcesHandler, readResourceHandler, listResourceTemplatesHandler, subscribeHandler, unsubscribeHandler, listToolsHandler, callToolHandler, completeHandler, loggingMessageSender, extraHandlers)
+	routerRouter, err := router.NewRouter(v, eventBus, iLogger, routerOptions)
 	if err != nil {
 		return nil, err
 	}